@@ -1,32 +1,167 @@
 package qbittorrent_api
 
 import (
-	"io"
+	"context"
+	"encoding/json"
 )
 
 type applicationApi struct {
-	client *Client
+	client       *Client
+	versionCache apiVersionCache
 }
 type applicationAction = string
 
 const (
-	actionVersion applicationAction = "version"
+	actionVersion                     applicationAction = "version"
+	actionWebAPIVersion               applicationAction = "webapiVersion"
+	actionBuildInfo                   applicationAction = "buildInfo"
+	actionShutdown                    applicationAction = "shutdown"
+	actionDefaultSavePath             applicationAction = "defaultSavePath"
+	actionNetworkInterfaceList        applicationAction = "networkInterfaceList"
+	actionNetworkInterfaceAddressList applicationAction = "networkInterfaceAddressList"
 )
 
 func (a *authorization) Version() (string, error) {
-	resp, err := a.get(apiNameApplication, actionVersion, nil)
+	return a.VersionContext(context.Background())
+}
+
+// VersionContext is like Version but carries ctx for cancellation.
+func (a *authorization) VersionContext(ctx context.Context) (string, error) {
+	resp, err := a.get(ctx, apiNameApplication, actionVersion, nil)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(apiNameApplication, actionVersion, resp)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// WebAPIVersion Retrieve the Web API version of qBittorrent, e.g. "2.8.3".
+func (a *applicationApi) WebAPIVersion() (string, error) {
+	return a.WebAPIVersionContext(context.Background())
+}
+
+// WebAPIVersionContext is like WebAPIVersion but carries ctx for cancellation.
+func (a *applicationApi) WebAPIVersionContext(ctx context.Context) (string, error) {
+	body, err := a.getForApp(ctx, actionWebAPIVersion, nil)
 	if err != nil {
 		return "", err
 	}
-	//fmt.Println(string(body))
-	//fmt.Println(resp.StatusCode)
-	if err := handleResponsesErr(resp.StatusCode); err != nil {
+	return string(body), nil
+}
+
+type BuildInfo struct {
+	Qt         string `json:"qt"`         // Qt version
+	Libtorrent string `json:"libtorrent"` // libtorrent version
+	Boost      string `json:"boost"`      // Boost version
+	OpenSSL    string `json:"openssl"`    // OpenSSL version
+	Zlib       string `json:"zlib"`       // zlib version
+	Bitness    int    `json:"bitness"`    // Application bitness (e.g. 64-bit)
+	Platform   string `json:"platform"`   // Operating system (added in qBittorrent 5.0)
+}
+
+// BuildInfo Retrieve the versions of the libraries qBittorrent was built with.
+func (a *applicationApi) BuildInfo() (*BuildInfo, error) {
+	return a.BuildInfoContext(context.Background())
+}
+
+// BuildInfoContext is like BuildInfo but carries ctx for cancellation.
+func (a *applicationApi) BuildInfoContext(ctx context.Context) (*BuildInfo, error) {
+	body, err := a.getForApp(ctx, actionBuildInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info BuildInfo
+	if err = json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Shutdown Shutdown qBittorrent.
+func (a *applicationApi) Shutdown() error {
+	return a.ShutdownContext(context.Background())
+}
+
+// ShutdownContext is like Shutdown but carries ctx for cancellation.
+func (a *applicationApi) ShutdownContext(ctx context.Context) error {
+	_, err := a.postForApp(ctx, actionShutdown, nil)
+	return err
+}
+
+// DefaultSavePath Retrieve the default path for saving torrents.
+func (a *applicationApi) DefaultSavePath() (string, error) {
+	return a.DefaultSavePathContext(context.Background())
+}
+
+// DefaultSavePathContext is like DefaultSavePath but carries ctx for cancellation.
+func (a *applicationApi) DefaultSavePathContext(ctx context.Context) (string, error) {
+	body, err := a.getForApp(ctx, actionDefaultSavePath, nil)
+	if err != nil {
 		return "", err
 	}
 	return string(body), nil
 }
+
+type NetworkInterface struct {
+	Name  string `json:"name"`  // display name, e.g. "eth0"
+	Value string `json:"value"` // identifier to use in preferences and NetworkInterfaceAddresses
+}
+
+// NetworkInterfaces List the network interfaces qBittorrent can bind to.
+func (a *applicationApi) NetworkInterfaces() ([]*NetworkInterface, error) {
+	return a.NetworkInterfacesContext(context.Background())
+}
+
+// NetworkInterfacesContext is like NetworkInterfaces but carries ctx for cancellation.
+func (a *applicationApi) NetworkInterfacesContext(ctx context.Context) ([]*NetworkInterface, error) {
+	body, err := a.getForApp(ctx, actionNetworkInterfaceList, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []*NetworkInterface
+	if err = json.Unmarshal(body, &interfaces); err != nil {
+		return nil, err
+	}
+	return interfaces, nil
+}
+
+// NetworkInterfaceAddresses List the addresses of a network interface,
+// an empty iface lists the addresses of all interfaces.
+func (a *applicationApi) NetworkInterfaceAddresses(iface string) ([]string, error) {
+	return a.NetworkInterfaceAddressesContext(context.Background(), iface)
+}
+
+// NetworkInterfaceAddressesContext is like NetworkInterfaceAddresses but carries ctx for cancellation.
+func (a *applicationApi) NetworkInterfaceAddressesContext(ctx context.Context, iface string) ([]string, error) {
+	body, err := a.getForApp(ctx, actionNetworkInterfaceAddressList, map[string]string{"iface": iface})
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	if err = json.Unmarshal(body, &addresses); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (a *applicationApi) getForApp(ctx context.Context, action applicationAction, data map[string]string) ([]byte, error) {
+	resp, err := a.client.request.get(ctx, apiNameApplication, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameApplication, action, resp)
+}
+
+func (a *applicationApi) postForApp(ctx context.Context, action applicationAction, data map[string]string) ([]byte, error) {
+	resp, err := a.client.request.post(ctx, apiNameApplication, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameApplication, action, resp)
+}
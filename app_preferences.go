@@ -0,0 +1,359 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+const (
+	actionPreferences    applicationAction = "preferences"
+	actionSetPreferences applicationAction = "setPreferences"
+)
+
+// Preferences of qBittorrent, see app/preferences in the Web API docs.
+//
+// All fields are pointers: fields returned by Preferences are set when the
+// server knows them, and SetPreferences only sends the fields which are not
+// nil, so a partial update is done by setting just the fields to change:
+//
+//	c.SetPreferences(&Preferences{DlLimit: Ptr(1024 * 1024), Upnp: Ptr(false)})
+type Preferences struct {
+	// Behavior
+	Locale               *string `json:"locale,omitempty"`
+	PerformanceWarning   *bool   `json:"performance_warning,omitempty"`
+	StatusBarExternalIP  *bool   `json:"status_bar_external_ip,omitempty"`
+	AppInstanceName      *string `json:"app_instance_name,omitempty"`
+	RefreshInterval      *int    `json:"refresh_interval,omitempty"`
+	FileLogEnabled       *bool   `json:"file_log_enabled,omitempty"`
+	FileLogPath          *string `json:"file_log_path,omitempty"`
+	FileLogBackupEnabled *bool   `json:"file_log_backup_enabled,omitempty"`
+	FileLogMaxSize       *int    `json:"file_log_max_size,omitempty"` // KiB
+	FileLogDeleteOld     *bool   `json:"file_log_delete_old,omitempty"`
+	FileLogAge           *int    `json:"file_log_age,omitempty"`
+	FileLogAgeType       *int    `json:"file_log_age_type,omitempty"` // 0 days, 1 months, 2 years
+
+	// Downloads
+	TorrentContentLayout         *ContentLayout            `json:"torrent_content_layout,omitempty"`
+	CreateSubfolderEnabled       *bool                     `json:"create_subfolder_enabled,omitempty"`
+	StartPausedEnabled           *bool                     `json:"start_paused_enabled,omitempty"` // before qBittorrent 5
+	AddStoppedEnabled            *bool                     `json:"add_stopped_enabled,omitempty"`  // since qBittorrent 5
+	AddToTopOfQueue              *bool                     `json:"add_to_top_of_queue,omitempty"`
+	TorrentStopCondition         *StopCondition            `json:"torrent_stop_condition,omitempty"`
+	AutoDeleteMode               *int                      `json:"auto_delete_mode,omitempty"` // 0 never, 1 if added, 2 always
+	PreallocateAll               *bool                     `json:"preallocate_all,omitempty"`
+	IncompleteFilesExt           *bool                     `json:"incomplete_files_ext,omitempty"`
+	AutoTmmEnabled               *bool                     `json:"auto_tmm_enabled,omitempty"`
+	TorrentChangedTmmEnabled     *bool                     `json:"torrent_changed_tmm_enabled,omitempty"`
+	SavePathChangedTmmEnabled    *bool                     `json:"save_path_changed_tmm_enabled,omitempty"`
+	CategoryChangedTmmEnabled    *bool                     `json:"category_changed_tmm_enabled,omitempty"`
+	UseSubcategories             *bool                     `json:"use_subcategories,omitempty"`
+	UseCategoryPathsInManualMode *bool                     `json:"use_category_paths_in_manual_mode,omitempty"`
+	SavePath                     *string                   `json:"save_path,omitempty"`
+	TempPathEnabled              *bool                     `json:"temp_path_enabled,omitempty"`
+	TempPath                     *string                   `json:"temp_path,omitempty"`
+	ScanDirs                     *map[string]ScanDirTarget `json:"scan_dirs,omitempty"` // watched folders, an empty map removes all
+	ExportDir                    *string                   `json:"export_dir,omitempty"`
+	ExportDirFin                 *string                   `json:"export_dir_fin,omitempty"`
+	ExcludedFileNamesEnabled     *bool                     `json:"excluded_file_names_enabled,omitempty"`
+	ExcludedFileNames            *string                   `json:"excluded_file_names,omitempty"`
+	MailNotificationEnabled      *bool                     `json:"mail_notification_enabled,omitempty"`
+	MailNotificationSender       *string                   `json:"mail_notification_sender,omitempty"`
+	MailNotificationEmail        *string                   `json:"mail_notification_email,omitempty"`
+	MailNotificationSMTP         *string                   `json:"mail_notification_smtp,omitempty"`
+	MailNotificationSSLEnabled   *bool                     `json:"mail_notification_ssl_enabled,omitempty"`
+	MailNotificationAuthEnabled  *bool                     `json:"mail_notification_auth_enabled,omitempty"`
+	MailNotificationUsername     *string                   `json:"mail_notification_username,omitempty"`
+	MailNotificationPassword     *string                   `json:"mail_notification_password,omitempty"`
+	AutorunOnTorrentAddedEnabled *bool                     `json:"autorun_on_torrent_added_enabled,omitempty"`
+	AutorunOnTorrentAddedProgram *string                   `json:"autorun_on_torrent_added_program,omitempty"`
+	AutorunEnabled               *bool                     `json:"autorun_enabled,omitempty"`
+	AutorunProgram               *string                   `json:"autorun_program,omitempty"`
+
+	// Connection
+	BittorrentProtocol   *int       `json:"bittorrent_protocol,omitempty"` // 0 TCP and μTP, 1 TCP, 2 μTP
+	ListenPort           *int       `json:"listen_port,omitempty"`
+	Upnp                 *bool      `json:"upnp,omitempty"`
+	RandomPort           *bool      `json:"random_port,omitempty"`
+	MaxConnec            *int       `json:"max_connec,omitempty"`
+	MaxConnecPerTorrent  *int       `json:"max_connec_per_torrent,omitempty"`
+	MaxUploads           *int       `json:"max_uploads,omitempty"`
+	MaxUploadsPerTorrent *int       `json:"max_uploads_per_torrent,omitempty"`
+	I2PEnabled           *bool      `json:"i2p_enabled,omitempty"`
+	I2PAddress           *string    `json:"i2p_address,omitempty"`
+	I2PPort              *int       `json:"i2p_port,omitempty"`
+	I2PMixedMode         *bool      `json:"i2p_mixed_mode,omitempty"`
+	ProxyType            *ProxyType `json:"proxy_type,omitempty"`
+	ProxyIP              *string    `json:"proxy_ip,omitempty"`
+	ProxyPort            *int       `json:"proxy_port,omitempty"`
+	ProxyAuthEnabled     *bool      `json:"proxy_auth_enabled,omitempty"`
+	ProxyUsername        *string    `json:"proxy_username,omitempty"`
+	ProxyPassword        *string    `json:"proxy_password,omitempty"`
+	ProxyHostnameLookup  *bool      `json:"proxy_hostname_lookup,omitempty"`
+	ProxyBittorrent      *bool      `json:"proxy_bittorrent,omitempty"`
+	ProxyPeerConnections *bool      `json:"proxy_peer_connections,omitempty"`
+	ProxyRSS             *bool      `json:"proxy_rss,omitempty"`
+	ProxyMisc            *bool      `json:"proxy_misc,omitempty"`
+	ProxyTorrentsOnly    *bool      `json:"proxy_torrents_only,omitempty"`
+	IPFilterEnabled      *bool      `json:"ip_filter_enabled,omitempty"`
+	IPFilterPath         *string    `json:"ip_filter_path,omitempty"`
+	IPFilterTrackers     *bool      `json:"ip_filter_trackers,omitempty"`
+	BannedIPs            *string    `json:"banned_IPs,omitempty"` // separated by \n
+
+	// Speed
+	DlLimit          *int  `json:"dl_limit,omitempty"` // bytes/second, 0 for no limit
+	UpLimit          *int  `json:"up_limit,omitempty"`
+	AltDlLimit       *int  `json:"alt_dl_limit,omitempty"`
+	AltUpLimit       *int  `json:"alt_up_limit,omitempty"`
+	LimitUTPRate     *bool `json:"limit_utp_rate,omitempty"`
+	LimitTCPOverhead *bool `json:"limit_tcp_overhead,omitempty"`
+	LimitLANPeers    *bool `json:"limit_lan_peers,omitempty"`
+	SchedulerEnabled *bool `json:"scheduler_enabled,omitempty"`
+	ScheduleFromHour *int  `json:"schedule_from_hour,omitempty"`
+	ScheduleFromMin  *int  `json:"schedule_from_min,omitempty"`
+	ScheduleToHour   *int  `json:"schedule_to_hour,omitempty"`
+	ScheduleToMin    *int  `json:"schedule_to_min,omitempty"`
+	SchedulerDays    *int  `json:"scheduler_days,omitempty"` // 0 every day, 1 weekdays, 2 weekends, 3 Monday ... 9 Sunday
+
+	// BitTorrent
+	DHT                           *bool    `json:"dht,omitempty"`
+	PeX                           *bool    `json:"pex,omitempty"`
+	LSD                           *bool    `json:"lsd,omitempty"`
+	Encryption                    *int     `json:"encryption,omitempty"` // 0 prefer, 1 force on, 2 force off
+	AnonymousMode                 *bool    `json:"anonymous_mode,omitempty"`
+	MaxActiveCheckingTorrents     *int     `json:"max_active_checking_torrents,omitempty"`
+	QueueingEnabled               *bool    `json:"queueing_enabled,omitempty"`
+	MaxActiveDownloads            *int     `json:"max_active_downloads,omitempty"`
+	MaxActiveTorrents             *int     `json:"max_active_torrents,omitempty"`
+	MaxActiveUploads              *int     `json:"max_active_uploads,omitempty"`
+	DontCountSlowTorrents         *bool    `json:"dont_count_slow_torrents,omitempty"`
+	SlowTorrentDlRateThreshold    *int     `json:"slow_torrent_dl_rate_threshold,omitempty"` // KiB/s
+	SlowTorrentUlRateThreshold    *int     `json:"slow_torrent_ul_rate_threshold,omitempty"` // KiB/s
+	SlowTorrentInactiveTimer      *int     `json:"slow_torrent_inactive_timer,omitempty"`    // seconds
+	MaxRatioEnabled               *bool    `json:"max_ratio_enabled,omitempty"`
+	MaxRatio                      *float64 `json:"max_ratio,omitempty"`
+	MaxSeedingTimeEnabled         *bool    `json:"max_seeding_time_enabled,omitempty"`
+	MaxSeedingTime                *int     `json:"max_seeding_time,omitempty"` // minutes
+	MaxInactiveSeedingTimeEnabled *bool    `json:"max_inactive_seeding_time_enabled,omitempty"`
+	MaxInactiveSeedingTime        *int     `json:"max_inactive_seeding_time,omitempty"` // minutes
+	MaxRatioAct                   *int     `json:"max_ratio_act,omitempty"`             // 0 pause (stop), 1 remove, 2 remove with files, 3 super seeding
+	AddTrackersEnabled            *bool    `json:"add_trackers_enabled,omitempty"`
+	AddTrackers                   *string  `json:"add_trackers,omitempty"` // separated by \n
+
+	// RSS
+	RSSRefreshInterval              *int    `json:"rss_refresh_interval,omitempty"` // minutes
+	RSSMaxArticlesPerFeed           *int    `json:"rss_max_articles_per_feed,omitempty"`
+	RSSProcessingEnabled            *bool   `json:"rss_processing_enabled,omitempty"`
+	RSSAutoDownloadingEnabled       *bool   `json:"rss_auto_downloading_enabled,omitempty"`
+	RSSDownloadRepackProperEpisodes *bool   `json:"rss_download_repack_proper_episodes,omitempty"`
+	RSSSmartEpisodeFilters          *string `json:"rss_smart_episode_filters,omitempty"`
+
+	// Web UI
+	WebUIDomainList                    *string `json:"web_ui_domain_list,omitempty"`
+	WebUIAddress                       *string `json:"web_ui_address,omitempty"`
+	WebUIPort                          *int    `json:"web_ui_port,omitempty"`
+	WebUIUpnp                          *bool   `json:"web_ui_upnp,omitempty"`
+	UseHTTPS                           *bool   `json:"use_https,omitempty"`
+	WebUIHTTPSCertPath                 *string `json:"web_ui_https_cert_path,omitempty"`
+	WebUIHTTPSKeyPath                  *string `json:"web_ui_https_key_path,omitempty"`
+	WebUIUsername                      *string `json:"web_ui_username,omitempty"`
+	WebUIPassword                      *string `json:"web_ui_password,omitempty"` // write only
+	BypassLocalAuth                    *bool   `json:"bypass_local_auth,omitempty"`
+	BypassAuthSubnetWhitelistEnabled   *bool   `json:"bypass_auth_subnet_whitelist_enabled,omitempty"`
+	BypassAuthSubnetWhitelist          *string `json:"bypass_auth_subnet_whitelist,omitempty"`
+	WebUIMaxAuthFailCount              *int    `json:"web_ui_max_auth_fail_count,omitempty"`
+	WebUIBanDuration                   *int    `json:"web_ui_ban_duration,omitempty"`    // seconds
+	WebUISessionTimeout                *int    `json:"web_ui_session_timeout,omitempty"` // seconds
+	AlternativeWebUIEnabled            *bool   `json:"alternative_webui_enabled,omitempty"`
+	AlternativeWebUIPath               *string `json:"alternative_webui_path,omitempty"`
+	WebUIClickjackingProtectionEnabled *bool   `json:"web_ui_clickjacking_protection_enabled,omitempty"`
+	WebUICSRFProtectionEnabled         *bool   `json:"web_ui_csrf_protection_enabled,omitempty"`
+	WebUISecureCookieEnabled           *bool   `json:"web_ui_secure_cookie_enabled,omitempty"`
+	WebUIHostHeaderValidationEnabled   *bool   `json:"web_ui_host_header_validation_enabled,omitempty"`
+	WebUIUseCustomHTTPHeadersEnabled   *bool   `json:"web_ui_use_custom_http_headers_enabled,omitempty"`
+	WebUICustomHTTPHeaders             *string `json:"web_ui_custom_http_headers,omitempty"`
+	WebUIReverseProxyEnabled           *bool   `json:"web_ui_reverse_proxy_enabled,omitempty"`
+	WebUIReverseProxiesList            *string `json:"web_ui_reverse_proxies_list,omitempty"`
+	DynDNSEnabled                      *bool   `json:"dyndns_enabled,omitempty"`
+	DynDNSService                      *int    `json:"dyndns_service,omitempty"` // 0 DynDNS, 1 NOIP
+	DynDNSUsername                     *string `json:"dyndns_username,omitempty"`
+	DynDNSPassword                     *string `json:"dyndns_password,omitempty"`
+	DynDNSDomain                       *string `json:"dyndns_domain,omitempty"`
+
+	// Advanced
+	ResumeDataStorageType            *string `json:"resume_data_storage_type,omitempty"`
+	MemoryWorkingSetLimit            *int    `json:"memory_working_set_limit,omitempty"` // MiB
+	CurrentNetworkInterface          *string `json:"current_network_interface,omitempty"`
+	CurrentInterfaceAddress          *string `json:"current_interface_address,omitempty"`
+	SaveResumeDataInterval           *int    `json:"save_resume_data_interval,omitempty"` // minutes
+	TorrentFileSizeLimit             *int    `json:"torrent_file_size_limit,omitempty"`   // bytes
+	RecheckCompletedTorrents         *bool   `json:"recheck_completed_torrents,omitempty"`
+	ResolvePeerCountries             *bool   `json:"resolve_peer_countries,omitempty"`
+	ReannounceWhenAddressChanged     *bool   `json:"reannounce_when_address_changed,omitempty"`
+	EnableEmbeddedTracker            *bool   `json:"enable_embedded_tracker,omitempty"`
+	EmbeddedTrackerPort              *int    `json:"embedded_tracker_port,omitempty"`
+	EmbeddedTrackerPortForwarding    *bool   `json:"embedded_tracker_port_forwarding,omitempty"`
+	MarkOfTheWeb                     *bool   `json:"mark_of_the_web,omitempty"`
+	PythonExecutablePath             *string `json:"python_executable_path,omitempty"`
+	BdecodeDepthLimit                *int    `json:"bdecode_depth_limit,omitempty"`
+	BdecodeTokenLimit                *int    `json:"bdecode_token_limit,omitempty"`
+	AsyncIOThreads                   *int    `json:"async_io_threads,omitempty"`
+	HashingThreads                   *int    `json:"hashing_threads,omitempty"`
+	FilePoolSize                     *int    `json:"file_pool_size,omitempty"`
+	CheckingMemoryUse                *int    `json:"checking_memory_use,omitempty"` // MiB
+	DiskCache                        *int    `json:"disk_cache,omitempty"`          // MiB, -1 automatic
+	DiskCacheTTL                     *int    `json:"disk_cache_ttl,omitempty"`      // seconds
+	DiskQueueSize                    *int    `json:"disk_queue_size,omitempty"`     // bytes
+	DiskIOType                       *int    `json:"disk_io_type,omitempty"`
+	DiskIOReadMode                   *int    `json:"disk_io_read_mode,omitempty"`
+	DiskIOWriteMode                  *int    `json:"disk_io_write_mode,omitempty"`
+	EnableOSCache                    *bool   `json:"enable_os_cache,omitempty"`
+	EnableCoalesceReadWrite          *bool   `json:"enable_coalesce_read_write,omitempty"`
+	EnablePieceExtentAffinity        *bool   `json:"enable_piece_extent_affinity,omitempty"`
+	EnableUploadSuggestions          *bool   `json:"enable_upload_suggestions,omitempty"`
+	SendBufferWatermark              *int    `json:"send_buffer_watermark,omitempty"`        // KiB
+	SendBufferLowWatermark           *int    `json:"send_buffer_low_watermark,omitempty"`    // KiB
+	SendBufferWatermarkFactor        *int    `json:"send_buffer_watermark_factor,omitempty"` // percent
+	ConnectionSpeed                  *int    `json:"connection_speed,omitempty"`             // connections per second
+	SocketSendBufferSize             *int    `json:"socket_send_buffer_size,omitempty"`      // bytes
+	SocketReceiveBufferSize          *int    `json:"socket_receive_buffer_size,omitempty"`   // bytes
+	SocketBacklogSize                *int    `json:"socket_backlog_size,omitempty"`
+	OutgoingPortsMin                 *int    `json:"outgoing_ports_min,omitempty"`
+	OutgoingPortsMax                 *int    `json:"outgoing_ports_max,omitempty"`
+	UpnpLeaseDuration                *int    `json:"upnp_lease_duration,omitempty"` // seconds
+	PeerToS                          *int    `json:"peer_tos,omitempty"`
+	UTPTCPMixedMode                  *int    `json:"utp_tcp_mixed_mode,omitempty"` // 0 prefer TCP, 1 peer proportional
+	IDNSupportEnabled                *bool   `json:"idn_support_enabled,omitempty"`
+	EnableMultiConnectionsFromSameIP *bool   `json:"enable_multi_connections_from_same_ip,omitempty"`
+	ValidateHTTPSTrackerCertificate  *bool   `json:"validate_https_tracker_certificate,omitempty"`
+	SSRFMitigation                   *bool   `json:"ssrf_mitigation,omitempty"`
+	BlockPeersOnPrivilegedPorts      *bool   `json:"block_peers_on_privileged_ports,omitempty"`
+	UploadSlotsBehavior              *int    `json:"upload_slots_behavior,omitempty"`    // 0 fixed slots, 1 upload rate based
+	UploadChokingAlgorithm           *int    `json:"upload_choking_algorithm,omitempty"` // 0 round-robin, 1 fastest upload, 2 anti-leech
+	AnnounceToAllTrackers            *bool   `json:"announce_to_all_trackers,omitempty"`
+	AnnounceToAllTiers               *bool   `json:"announce_to_all_tiers,omitempty"`
+	AnnounceIP                       *string `json:"announce_ip,omitempty"`
+	MaxConcurrentHTTPAnnounces       *int    `json:"max_concurrent_http_announces,omitempty"`
+	StopTrackerTimeout               *int    `json:"stop_tracker_timeout,omitempty"`   // seconds
+	PeerTurnover                     *int    `json:"peer_turnover,omitempty"`          // percent
+	PeerTurnoverCutoff               *int    `json:"peer_turnover_cutoff,omitempty"`   // percent
+	PeerTurnoverInterval             *int    `json:"peer_turnover_interval,omitempty"` // seconds
+	RequestQueueSize                 *int    `json:"request_queue_size,omitempty"`
+	DHTBootstrapNodes                *string `json:"dht_bootstrap_nodes,omitempty"`
+	I2PInboundQuantity               *int    `json:"i2p_inbound_quantity,omitempty"`
+	I2POutboundQuantity              *int    `json:"i2p_outbound_quantity,omitempty"`
+	I2PInboundLength                 *int    `json:"i2p_inbound_length,omitempty"`
+	I2POutboundLength                *int    `json:"i2p_outbound_length,omitempty"`
+}
+
+// Ptr returns a pointer to v, it helps filling in Preferences.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// ProxyType is the proxy_type preference. qBittorrent before 4.6 uses the
+// numbers -1 to 5, later versions the names below.
+type ProxyType string
+
+const (
+	ProxyTypeNone   ProxyType = "None"
+	ProxyTypeHTTP   ProxyType = "HTTP"
+	ProxyTypeSOCKS5 ProxyType = "SOCKS5"
+	ProxyTypeSOCKS4 ProxyType = "SOCKS4"
+)
+
+func (p *ProxyType) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*p = ProxyType(strconv.Itoa(number))
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*p = ProxyType(name)
+	return nil
+}
+
+// MarshalJSON keeps numeric proxy types numeric for older servers.
+func (p ProxyType) MarshalJSON() ([]byte, error) {
+	if number, err := strconv.Atoi(string(p)); err == nil {
+		return json.Marshal(number)
+	}
+	return json.Marshal(string(p))
+}
+
+// ScanDirTarget is where torrents found in a watched folder are saved,
+// ScanDirMonitoredFolder, ScanDirDefaultSavePath or a path.
+type ScanDirTarget string
+
+const (
+	ScanDirMonitoredFolder ScanDirTarget = "0" // save into the watched folder
+	ScanDirDefaultSavePath ScanDirTarget = "1" // save into the default save path
+)
+
+func (t *ScanDirTarget) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*t = ScanDirTarget(strconv.Itoa(number))
+		return nil
+	}
+	var path string
+	if err := json.Unmarshal(data, &path); err != nil {
+		return err
+	}
+	*t = ScanDirTarget(path)
+	return nil
+}
+
+// MarshalJSON sends the two fixed targets as the numbers the server expects.
+func (t ScanDirTarget) MarshalJSON() ([]byte, error) {
+	switch t {
+	case ScanDirMonitoredFolder, ScanDirDefaultSavePath:
+		number, _ := strconv.Atoi(string(t))
+		return json.Marshal(number)
+	}
+	return json.Marshal(string(t))
+}
+
+// Preferences
+// Retrieve qBittorrent application preferences.
+func (a *applicationApi) Preferences() (*Preferences, error) {
+	return a.PreferencesContext(context.Background())
+}
+
+// PreferencesContext is like Preferences but carries ctx for cancellation.
+func (a *applicationApi) PreferencesContext(ctx context.Context) (*Preferences, error) {
+	body, err := a.getForApp(ctx, actionPreferences, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferences Preferences
+	if err = json.Unmarshal(body, &preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// SetPreferences
+// Set one or more preferences in qBittorrent application. Only the fields
+// which are not nil are sent, all other preferences keep their value.
+func (a *applicationApi) SetPreferences(preferences *Preferences) error {
+	return a.SetPreferencesContext(context.Background(), preferences)
+}
+
+// SetPreferencesContext is like SetPreferences but carries ctx for cancellation.
+func (a *applicationApi) SetPreferencesContext(ctx context.Context, preferences *Preferences) error {
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return err
+	}
+	if string(data) == "{}" {
+		return nil
+	}
+
+	_, err = a.postForApp(ctx, actionSetPreferences, map[string]string{"json": string(data)})
+	return err
+}
@@ -1,6 +1,7 @@
 package qbittorrent_api
 
 import (
+	"net/http"
 	"testing"
 )
 
@@ -12,3 +13,82 @@ func TestVersion(t *testing.T) {
 		t.Log(version)
 	}
 }
+
+func TestPreferences(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("app/preferences", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"save_path":"/downloads","dl_limit":0,"dht":true,"max_ratio":1.5,"proxy_type":-1,"scan_dirs":{"/watch":1}}`))
+	})
+	var posted string
+	srv.handle("app/setPreferences", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		posted = r.PostForm.Get("json")
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	p, err := c.Preferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p.SavePath != "/downloads" || *p.DlLimit != 0 || !*p.DHT || *p.MaxRatio != 1.5 || *p.ProxyType != "-1" || p.Upnp != nil {
+		t.Errorf("unexpected preferences %+v", p)
+	}
+	if p.ScanDirs == nil || (*p.ScanDirs)["/watch"] != ScanDirDefaultSavePath {
+		t.Errorf("unexpected scan dirs %v", p.ScanDirs)
+	}
+
+	if err := c.SetPreferences(&Preferences{DlLimit: Ptr(1024), Upnp: Ptr(false), ProxyType: Ptr(ProxyTypeSOCKS5)}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"upnp":false,"proxy_type":"SOCKS5","dl_limit":1024}`; posted != want {
+		t.Errorf("posted %s, want %s", posted, want)
+	}
+
+	scanDirs := map[string]ScanDirTarget{"/a": ScanDirMonitoredFolder, "/b": "/downloads/b"}
+	if err := c.SetPreferences(&Preferences{ScanDirs: &scanDirs}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"scan_dirs":{"/a":0,"/b":"/downloads/b"}}`; posted != want {
+		t.Errorf("posted %s, want %s", posted, want)
+	}
+	// an empty map clears the watched folders
+	if err := c.SetPreferences(&Preferences{ScanDirs: Ptr(map[string]ScanDirTarget{})}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"scan_dirs":{}}`; posted != want {
+		t.Errorf("posted %s, want %s", posted, want)
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("app/buildInfo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bitness":64,"boost":"1.83.0","libtorrent":"2.0.9.0","openssl":"3.1.4","qt":"6.6.1","zlib":"1.3"}`))
+	})
+	srv.handle("app/networkInterfaceAddressList", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("iface") != "eth0" {
+			http.Error(w, "unknown interface", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`["192.168.1.2","fe80::1"]`))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	info, err := c.BuildInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (BuildInfo{Qt: "6.6.1", Libtorrent: "2.0.9.0", Boost: "1.83.0", OpenSSL: "3.1.4", Zlib: "1.3", Bitness: 64}); *info != want {
+		t.Errorf("BuildInfo() = %+v, want %+v", *info, want)
+	}
+
+	addresses, err := c.NetworkInterfaceAddresses("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addresses) != 2 || addresses[1] != "fe80::1" {
+		t.Errorf("NetworkInterfaceAddresses() = %v", addresses)
+	}
+}
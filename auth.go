@@ -1,55 +1,143 @@
 package qbittorrent_api
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
-	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 type authorization struct {
+	// mu guards the session state below. It also makes concurrent requests
+	// hitting an expired session share a single login call.
+	mu         sync.Mutex
 	isLoggedIn bool
 	username   string
 	password   string
+	relogin    *loginCall
 	request
 }
 
+type loginCall struct {
+	done chan struct{}
+	err  error
+}
+
 func (a *authorization) IsLoggedIn() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	return a.isLoggedIn
 }
 
-func (a *authorization) Login(username, password string) error {
+func (a *authorization) setCredentials(username, password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.username = username
 	a.password = password
-	resp, err := a.post(apiNameAuthorization, "login", map[string]string{
-		"username": a.username,
-		"password": a.password,
+}
+
+func (a *authorization) Login(username, password string) error {
+	return a.LoginContext(context.Background(), username, password)
+}
+
+// LoginContext is like Login but carries ctx for cancellation.
+func (a *authorization) LoginContext(ctx context.Context, username, password string) error {
+	a.setCredentials(username, password)
+	resp, err := a.post(ctx, apiNameAuthorization, "login", map[string]string{
+		"username": username,
+		"password": password,
 	})
 	if err != nil {
 		logrus.Error("Login failed")
 		return err
 	}
-	defer resp.Body.Close()
+	body, err := readBody(apiNameAuthorization, "login", resp)
+	if err != nil {
+		logrus.Error("Login failed")
+		return err
+	}
+	// qbittorrent answers 200 with "Fails." for wrong credentials
+	if string(body) == "Fails." {
+		logrus.Error("Login failed")
+		return ErrLoginFailed
+	}
 
 	logrus.Debug("Login successful")
-	a.isLoggedIn = true
 
 	if cookies := resp.Cookies(); len(cookies) > 0 {
 		cookieURL, _ := url.Parse(a.host)
 		a.request.Jar.SetCookies(cookieURL, cookies)
 	}
 
-	// create a new client with the cookie jar and replace the old one
-	// so that all our later requests are authenticated
-	a.http = &http.Client{
-		Jar: a.Jar,
-	}
+	a.mu.Lock()
+	a.isLoggedIn = true
+	a.request.generation.Add(1)
+	a.mu.Unlock()
 	return nil
 }
 
+// reLoginTimeout bounds the shared login of reLogin, which doesn't belong to
+// any single caller's context.
+const reLoginTimeout = 30 * time.Second
+
+// reLogin renews an expired session with the stored credentials. Callers
+// that arrive while a login is in flight wait for its result instead of
+// starting another one, and callers whose request was sent before the last
+// successful login just replay their request. The login itself runs with
+// its own timeout, so a caller giving up only stops its own wait.
+func (a *authorization) reLogin(ctx context.Context, generation uint64) error {
+	a.mu.Lock()
+	if a.request.generation.Load() != generation {
+		a.mu.Unlock()
+		return nil
+	}
+	call := a.relogin
+	if call == nil {
+		if a.username == "" && a.password == "" {
+			a.mu.Unlock()
+			return errNoCredentials
+		}
+		call = &loginCall{done: make(chan struct{})}
+		a.relogin = call
+		go a.runLogin(call, a.username, a.password)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *authorization) runLogin(call *loginCall, username, password string) {
+	ctx, cancel := context.WithTimeout(context.Background(), reLoginTimeout)
+	defer cancel()
+
+	logrus.Debug("session expired, logging in again")
+	call.err = a.LoginContext(ctx, username, password)
+
+	a.mu.Lock()
+	a.relogin = nil
+	a.mu.Unlock()
+	close(call.done)
+}
+
 func (a *authorization) Logout() {
-	resp, err := a.post(apiNameAuthorization, "logout", nil)
+	a.LogoutContext(context.Background())
+}
+
+// LogoutContext is like Logout but carries ctx for cancellation.
+func (a *authorization) LogoutContext(ctx context.Context) {
+	resp, err := a.post(ctx, apiNameAuthorization, "logout", nil)
 	if err == nil {
 		resp.Body.Close()
 	}
-	a.request.initialize()
+
+	a.mu.Lock()
+	a.isLoggedIn = false
+	a.mu.Unlock()
+	a.request.clearCookies()
 }
@@ -1,6 +1,15 @@
 package qbittorrent_api
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
 
 func TestLogin(t *testing.T) {
 	a := authorization{
@@ -13,3 +22,125 @@ func TestLogin(t *testing.T) {
 	a.request.initialize()
 	a.Login(name, pwd)
 }
+
+func TestReLoginOnForbidden(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("app/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v4.4.3.1"))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	if _, err := c.Version(); err != nil {
+		t.Fatal(err)
+	}
+	srv.expire()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Version(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := srv.loginCount(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+}
+
+func TestReLoginSurvivesCanceledCaller(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("app/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v4.4.3.1"))
+	})
+
+	// hold the re-login until the first caller has given up
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "auth/login") && srv.loginCount() > 0 {
+			once.Do(func() { close(started) })
+			<-release
+		}
+		srv.serve(w, r)
+	}))
+	defer proxy.Close()
+
+	c := NewClient(proxy.URL, WithAuth(name, pwd))
+	if _, err := c.Version(); err != nil {
+		t.Fatal(err)
+	}
+	srv.expire()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.VersionContext(ctx)
+		first <- err
+	}()
+	<-started
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.Version()
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller: err = %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("second caller: %v", err)
+	}
+	if n := srv.loginCount(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+}
+
+func TestForbiddenWithoutCredentials(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+
+	if _, err := NewClient(srv.URL).Version(); !errors.Is(err, ErrForbidden) {
+		t.Errorf("err = %v, want %v", err, ErrForbidden)
+	}
+	if n := srv.loginCount(); n != 0 {
+		t.Errorf("logins = %d, want 0", n)
+	}
+}
+
+func TestForbiddenWithoutExpiredSession(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var calls int32
+	srv.handle("torrents/setLocation", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "Cannot write to directory", http.StatusForbidden)
+	})
+	srv.handle("app/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v4.4.3.1"))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	if _, err := c.Version(); err != nil {
+		t.Fatal(err)
+	}
+	logins := srv.loginCount()
+	err := c.SetLocationByHashes("/readonly", []string{"aaa"})
+	if !errors.Is(err, ErrForbidden) || !strings.Contains(err.Error(), "Cannot write to directory") {
+		t.Errorf("err = %v, want %v with the server's reason", err, ErrForbidden)
+	}
+	if n := srv.loginCount(); n != logins {
+		t.Errorf("logins = %d, want %d", n, logins)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("setLocation sent %d times, want 1", n)
+	}
+}
@@ -1,9 +1,22 @@
 package qbittorrent_api
 
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+)
+
+// Client is a qBittorrent Web API client. It is safe for concurrent use by
+// multiple goroutines once NewClient returned, Options are only applied
+// during construction.
 type Client struct {
 	authorization
 	applicationApi
 	torrentsApi
+	syncApi
+	transferApi
+	logApi
 }
 
 type Option func(client *Client)
@@ -19,8 +32,12 @@ func NewClient(host string, opts ...Option) *Client {
 		},
 	}
 	c.request.initialize()
+	c.request.reauth = c.authorization.reLogin
 	c.torrentsApi.client = c
 	c.applicationApi.client = c
+	c.syncApi.client = c
+	c.transferApi.client = c
+	c.logApi.client = c
 
 	for _, opt := range opts {
 		opt(c)
@@ -31,7 +48,75 @@ func NewClient(host string, opts ...Option) *Client {
 
 func WithAuth(username, password string) Option {
 	return func(client *Client) {
-		client.authorization.username = username
-		client.authorization.password = password
+		client.authorization.setCredentials(username, password)
+	}
+}
+
+// WithHTTPClient sends all requests through a copy of hc, keeping its
+// timeout, transport and redirect policy. If hc has no cookie jar the
+// session jar of the Client is used, otherwise hc's jar holds the session.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(client *Client) {
+		c := *hc
+		if c.Jar == nil {
+			c.Jar = client.request.Jar
+		} else {
+			client.request.Jar = c.Jar
+		}
+		client.request.setHTTPClient(&c)
+	}
+}
+
+// WithTransport replaces the RoundTripper used for all requests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(client *Client) {
+		client.request.updateHTTPClient(func(hc *http.Client) {
+			hc.Transport = rt
+		})
+	}
+}
+
+// WithTimeout limits the time of every single request, including
+// reading the response body. Zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		client.request.updateHTTPClient(func(hc *http.Client) {
+			hc.Timeout = timeout
+		})
+	}
+}
+
+// WithTLSConfig uses cfg for https connections.
+// A RoundTripper other than *http.Transport is replaced by a default transport.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(client *Client) {
+		client.request.transport().TLSClientConfig = cfg
+	}
+}
+
+// WithRootCAs trusts only the certificates in pool, e.g. the self-signed CA
+// of the WebUI.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(client *Client) {
+		t := client.request.transport()
+		t.TLSClientConfig = tlsConfigOf(t)
+		t.TLSClientConfig.RootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate.
+// Only use it for testing or on trusted networks.
+func WithInsecureSkipVerify() Option {
+	return func(client *Client) {
+		t := client.request.transport()
+		t.TLSClientConfig = tlsConfigOf(t)
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+}
+
+func tlsConfigOf(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		return &tls.Config{}
 	}
+	return t.TLSClientConfig.Clone()
 }
@@ -1,8 +1,22 @@
 package qbittorrent_api
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const (
@@ -153,3 +167,360 @@ func TestTorrentFiles(t *testing.T) {
 	}
 
 }
+
+func TestTorrentsContextTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	c := NewClient(srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.TorrentsContext(ctx, &Filter{}); err == nil {
+		t.Error("expected error from timed out context")
+	}
+}
+
+func TestWithRootCAs(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v4.4.3.1"))
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient(srv.URL).Version(); err == nil {
+		t.Error("expected certificate error without pinned CA")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	if _, err := NewClient(srv.URL, WithRootCAs(pool)).Version(); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewClient(srv.URL, WithInsecureSkipVerify()).Version(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoginKeepsHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+		w.Write([]byte("Ok."))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithTimeout(3*time.Second))
+	if err := c.Login(name, pwd); err != nil {
+		t.Fatal(err)
+	}
+	if c.request.httpClient().Timeout != 3*time.Second {
+		t.Errorf("timeout = %v after Login", c.request.httpClient().Timeout)
+	}
+	c.Logout()
+	if c.request.httpClient().Timeout != 3*time.Second {
+		t.Errorf("timeout = %v after Logout", c.request.httpClient().Timeout)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"hash":"e40127b663555092b5ac7b1f621cb2a7364adbe1","state":"downloading"}]`))
+	})
+	srv.handle("torrents/pause", func(w http.ResponseWriter, r *http.Request) {})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				// a logout racing with the replay may still surface as 403
+				if _, err := c.Torrents(&Filter{}); err != nil && !errors.Is(err, ErrForbidden) {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := c.PauseByHashes([]string{"e40127b663555092b5ac7b1f621cb2a7364adbe1"}); err != nil && !errors.Is(err, ErrForbidden) {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := c.Login(name, pwd); err != nil {
+					t.Error(err)
+				}
+				c.IsLoggedIn()
+				c.Logout()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAPIError(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("torrents/createCategory", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unable to create category", http.StatusConflict)
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	err := c.CreateCategory(Category{Name: "movies"})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want %v", err, ErrConflict)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %T, want *APIError", err)
+	}
+	if apiErr.Method != http.MethodPost || apiErr.API != apiNameTorrents || apiErr.Action != actionCreateCategory ||
+		apiErr.StatusCode != http.StatusConflict || apiErr.Body != "Unable to create category" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+func TestDownloadFromFile(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+
+	var got = map[string]string{}
+	var category string
+	srv.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			if part.FormName() == "torrents" {
+				got[part.FileName()] = string(data)
+			} else if part.FormName() == "category" {
+				category = string(data)
+			}
+		}
+		w.Write([]byte("Ok."))
+	})
+
+	path := filepath.Join(t.TempDir(), "a.torrent")
+	if err := os.WriteFile(path, []byte("file contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	err := c.DownloadFromFile(TorrentDLConfig{
+		File: path,
+		Torrents: []TorrentUpload{
+			TorrentFromBytes("b.torrent", []byte("byte contents")),
+			TorrentFromReader("c.torrent", io.MultiReader(strings.NewReader("reader contents"))),
+			TorrentFromBytes("", []byte("unnamed")),
+		},
+		DownloadBaseConfig: DownloadBaseConfig{Category: "movies"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"a.torrent":  "file contents",
+		"b.torrent":  "byte contents",
+		"c.torrent":  "reader contents",
+		"torrent__4": "unnamed",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uploaded %v, want %v", got, want)
+	}
+	if category != "movies" {
+		t.Errorf("category = %q", category)
+	}
+
+	// readers are replayed after the session expired, seekable ones from
+	// the offset they were passed at
+	srv.expire()
+	logins := srv.loginCount()
+	seekable := strings.NewReader("skip:seekable contents")
+	seekable.Seek(5, io.SeekStart)
+	got = map[string]string{}
+	err = c.DownloadFromFile(TorrentDLConfig{Torrents: []TorrentUpload{
+		TorrentFromReader("d.torrent", seekable),
+		TorrentFromReader("e.torrent", io.MultiReader(strings.NewReader("stream contents"))),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"d.torrent": "seekable contents", "e.torrent": "stream contents"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uploaded %v, want %v", got, want)
+	}
+	if srv.loginCount() != logins+1 {
+		t.Errorf("logged in %d times, want 1", srv.loginCount()-logins)
+	}
+
+	if err := c.DownloadFromFile(TorrentDLConfig{File: filepath.Join(t.TempDir(), "missing.torrent")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestMultipartLength(t *testing.T) {
+	files, err := replayableUploads([]TorrentUpload{TorrentFromBytes("a.torrent", []byte("0123456789")), TorrentFromReader("b", strings.NewReader("abc"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := map[string]string{"category": "movies", "tags": "a,b"}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writeMultipartFiles(writer, files, params); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := multipartLength(writer.Boundary(), files, params); !ok || n != int64(buf.Len()) {
+		t.Errorf("length = %d, %v, want %d", n, ok, buf.Len())
+	}
+}
+
+func TestDownloadBaseConfigToMap(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  DownloadBaseConfig
+		want map[string]string
+	}{
+		{
+			name: "defaults",
+			cfg:  DownloadBaseConfig{},
+			want: map[string]string{},
+		},
+		{
+			name: "explicit false",
+			cfg:  DownloadBaseConfig{SequentialDownload: TriStateFalse, Paused: TriStateOf(false)},
+			want: map[string]string{"sequentialDownload": "false", "paused": "false"},
+		},
+		{
+			name: "auto tmm ignores save path",
+			cfg:  DownloadBaseConfig{SavePath: "/bt", AutoTMM: TriStateTrue},
+			want: map[string]string{"autoTMM": "true"},
+		},
+		{
+			name: "download path and limits",
+			cfg: DownloadBaseConfig{
+				SavePath:                 "/bt",
+				DownloadPath:             "/incomplete",
+				RatioLimit:               1.5,
+				SeedingTimeLimit:         -2,
+				InactiveSeedingTimeLimit: 60,
+				StopCondition:            StopConditionMetadataReceived,
+				AddToTopOfQueue:          TriStateTrue,
+			},
+			want: map[string]string{
+				"savepath":                 "/bt",
+				"downloadPath":             "/incomplete",
+				"useDownloadPath":          "true",
+				"ratioLimit":               "1.5",
+				"seedingTimeLimit":         "-2",
+				"inactiveSeedingTimeLimit": "60",
+				"stopCondition":            "MetadataReceived",
+				"addToTopOfQueue":          "true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.toMap(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPauseResumeByVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		pause        string
+		resume       string
+		pausedParam  string
+		pausedFilter string
+	}{
+		{version: "2.8.19", pause: "torrents/pause", resume: "torrents/resume", pausedParam: "paused", pausedFilter: "paused"},
+		{version: "2.11.2", pause: "torrents/stop", resume: "torrents/start", pausedParam: "stopped", pausedFilter: "stopped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			srv := newFakeServerWithVersion(tt.version)
+			defer srv.Close()
+
+			var called []string
+			record := func(w http.ResponseWriter, r *http.Request) {
+				called = append(called, strings.TrimPrefix(r.URL.Path, "/api/v2/"))
+			}
+			srv.handle(tt.pause, record)
+			srv.handle(tt.resume, record)
+			srv.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+				r.ParseMultipartForm(1 << 20)
+				if r.FormValue(tt.pausedParam) != "true" {
+					t.Errorf("add params %v, want %s=true", r.MultipartForm.Value, tt.pausedParam)
+				}
+				w.Write([]byte("Ok."))
+			})
+			srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				if filter := r.FormValue("filter"); filter != tt.pausedFilter {
+					t.Errorf("filter = %s, want %s", filter, tt.pausedFilter)
+				}
+				w.Write([]byte("[]"))
+			})
+
+			c := NewClient(srv.URL, WithAuth(name, pwd))
+			hash := "e40127b663555092b5ac7b1f621cb2a7364adbe1"
+			if err := c.Pause(&Torrent{Hash: hash}); err != nil {
+				t.Error(err)
+			}
+			if err := c.ResumeAll(); err != nil {
+				t.Error(err)
+			}
+			if want := []string{tt.pause, tt.resume}; !reflect.DeepEqual(called, want) {
+				t.Errorf("called %v, want %v", called, want)
+			}
+			if err := c.DownloadFromLink(MagnetDLConfig{
+				Urls:               []string{"magnet:?xt=urn:btih:" + hash},
+				DownloadBaseConfig: DownloadBaseConfig{Paused: TriStateTrue},
+			}); err != nil {
+				t.Error(err)
+			}
+			for _, filter := range []string{"paused", "stopped"} {
+				if _, err := c.Torrents(&Filter{StatusFilter: filter}); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestTorrentStateHelpers(t *testing.T) {
+	for _, state := range []TorrentState{StatePausedUpload, StateStoppedUpload} {
+		torrent := Torrent{State: state}
+		if !torrent.IsPaused() || !torrent.IsComplete() || torrent.IsDownloading() {
+			t.Errorf("unexpected helpers for %s", state)
+		}
+	}
+	for _, state := range []TorrentState{StatePausedDownload, StateStoppedDownload} {
+		torrent := Torrent{State: state}
+		if !torrent.IsPaused() || torrent.IsComplete() || !torrent.IsDownloading() {
+			t.Errorf("unexpected helpers for %s", state)
+		}
+	}
+	if (Torrent{State: StateUploading}).IsPaused() {
+		t.Error("uploading torrent reported as paused")
+	}
+}
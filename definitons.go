@@ -15,6 +15,9 @@ const (
 )
 
 const (
-	GoQBitVersion  = "v1.0.0"
+	GoQBitVersion = "v1.0.0"
+	// WebQBitVersion is the qBittorrent version this library was written
+	// against, the Web API version of the connected server is available
+	// from Client.ServerAPIVersion.
 	WebQBitVersion = "v4.4.3.1"
 )
@@ -1,8 +1,10 @@
 package qbittorrent_api
 
 import (
+	"fmt"
 	"github.com/pkg/errors"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -15,6 +17,13 @@ var (
 	ErrConflict             = errors.New("Conflict")
 	ErrUnsupportedMediaType = errors.New("UnsupportedMediaType")
 	ErrInternalServerError  = errors.New("InternalServerError")
+	ErrLoginFailed          = errors.New("LoginFailed")
+	ErrUnsupportedByServer  = errors.New("UnsupportedByServer")
+	ErrTorrentNotAdded      = errors.New("TorrentNotAdded")
+
+	// errNoCredentials is returned when a session can't be renewed because
+	// neither WithAuth nor Login provided credentials.
+	errNoCredentials = errors.New("NoCredentials")
 )
 
 func handleResponsesErr(statusCode int) error {
@@ -41,3 +50,52 @@ func handleResponsesErr(statusCode int) error {
 	}
 	return ErrInternalServerError
 }
+
+// APIError describes a failed call of the Web API. It unwraps to one of the
+// sentinel errors above, so errors.Is(err, ErrConflict) keeps working.
+type APIError struct {
+	Method     string  // HTTP method of the request
+	API        apiName // API name, e.g. "torrents"
+	Action     string  // API action, e.g. "createCategory"
+	StatusCode int     // HTTP status code of the response
+	Body       string  // response body, qbittorrent puts the reason here
+	err        error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s/%s: %d %s", e.Method, e.API, e.Action, e.StatusCode, e.err)
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// maxErrorBodyLength limits how much of a response body is kept in an APIError.
+const maxErrorBodyLength = 512
+
+// newAPIError returns an *APIError for error status codes and nil otherwise.
+func newAPIError(name apiName, action string, resp *http.Response, body []byte) error {
+	err := handleResponsesErr(resp.StatusCode)
+	if err == nil {
+		return nil
+	}
+
+	apiErr := &APIError{
+		API:        name,
+		Action:     action,
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+		err:        err,
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+	}
+	if len(apiErr.Body) > maxErrorBodyLength {
+		apiErr.Body = apiErr.Body[:maxErrorBodyLength] + "..."
+	}
+	return apiErr
+}
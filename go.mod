@@ -5,9 +5,7 @@ go 1.19
 require (
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.9.0
+	golang.org/x/net v0.8.0
 )
 
-require (
-	golang.org/x/net v0.8.0 // indirect
-	golang.org/x/sys v0.6.0 // indirect
-)
+require golang.org/x/sys v0.6.0 // indirect
@@ -0,0 +1,213 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type logApi struct {
+	client *Client
+}
+type logAction = string
+
+const (
+	actionMainLog logAction = "main"
+	actionPeerLog logAction = "peers"
+)
+
+// LogType is the severity of a main log entry. The values are bit flags,
+// so they can be combined to filter MainLog.
+type LogType int
+
+const (
+	LogNormal   LogType = 1 << iota // 1
+	LogInfo                         // 2
+	LogWarning                      // 4
+	LogCritical                     // 8
+
+	LogAll = LogNormal | LogInfo | LogWarning | LogCritical
+)
+
+func (l LogType) String() string {
+	switch l {
+	case LogNormal:
+		return "normal"
+	case LogInfo:
+		return "info"
+	case LogWarning:
+		return "warning"
+	case LogCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// NoLogID requests the whole log from MainLog and PeerLog.
+const NoLogID int64 = -1
+
+type LogEntry struct {
+	ID      int64
+	Message string
+	Time    time.Time
+	Type    LogType
+}
+
+func (e *LogEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        int64   `json:"id"`
+		Message   string  `json:"message"`
+		Timestamp int64   `json:"timestamp"` // seconds since epoch
+		Type      LogType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = LogEntry{ID: raw.ID, Message: raw.Message, Time: time.Unix(raw.Timestamp, 0), Type: raw.Type}
+	return nil
+}
+
+type PeerLogEntry struct {
+	ID      int64
+	IP      string
+	Time    time.Time
+	Blocked bool   // true if the peer was blocked, false if it was banned
+	Reason  string // reason of the block
+}
+
+func (e *PeerLogEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        int64  `json:"id"`
+		IP        string `json:"ip"`
+		Timestamp int64  `json:"timestamp"` // seconds since epoch
+		Blocked   bool   `json:"blocked"`
+		Reason    string `json:"reason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = PeerLogEntry{ID: raw.ID, IP: raw.IP, Time: time.Unix(raw.Timestamp, 0), Blocked: raw.Blocked, Reason: raw.Reason}
+	return nil
+}
+
+// MainLog Retrieve the main log entries of the given types newer than
+// lastKnownID, pass NoLogID for the whole log. Zero types means LogAll.
+func (l *logApi) MainLog(types LogType, lastKnownID int64) ([]*LogEntry, error) {
+	return l.MainLogContext(context.Background(), types, lastKnownID)
+}
+
+// MainLogContext is like MainLog but carries ctx for cancellation.
+func (l *logApi) MainLogContext(ctx context.Context, types LogType, lastKnownID int64) ([]*LogEntry, error) {
+	if types == 0 {
+		types = LogAll
+	}
+	body, err := l.getForLog(ctx, actionMainLog, map[string]string{
+		"normal":        strconv.FormatBool(types&LogNormal != 0),
+		"info":          strconv.FormatBool(types&LogInfo != 0),
+		"warning":       strconv.FormatBool(types&LogWarning != 0),
+		"critical":      strconv.FormatBool(types&LogCritical != 0),
+		"last_known_id": strconv.FormatInt(lastKnownID, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*LogEntry
+	if err = json.Unmarshal(body, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// PeerLog Retrieve the peer log entries newer than lastKnownID,
+// pass NoLogID for the whole log.
+func (l *logApi) PeerLog(lastKnownID int64) ([]*PeerLogEntry, error) {
+	return l.PeerLogContext(context.Background(), lastKnownID)
+}
+
+// PeerLogContext is like PeerLog but carries ctx for cancellation.
+func (l *logApi) PeerLogContext(ctx context.Context, lastKnownID int64) ([]*PeerLogEntry, error) {
+	body, err := l.getForLog(ctx, actionPeerLog, map[string]string{
+		"last_known_id": strconv.FormatInt(lastKnownID, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*PeerLogEntry
+	if err = json.Unmarshal(body, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// TailMainLog polls the main log and sends new entries of the given types on
+// the returned channel, which is closed once ctx is done. The existing log
+// is only sent if wc.EmitExisting is set.
+func (l *logApi) TailMainLog(ctx context.Context, types LogType, wc WatchConfig) <-chan *LogEntry {
+	entries := make(chan *LogEntry)
+	lastID := NoLogID
+	go func() {
+		defer close(entries)
+		watchLoop(ctx, wc, func(first bool) error {
+			batch, err := l.MainLogContext(ctx, types, lastID)
+			if err != nil {
+				return err
+			}
+			for _, entry := range batch {
+				if entry.ID > lastID {
+					lastID = entry.ID
+				}
+				if first && !wc.EmitExisting {
+					continue
+				}
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return nil
+		})
+	}()
+	return entries
+}
+
+// TailPeerLog is like TailMainLog for the peer log.
+func (l *logApi) TailPeerLog(ctx context.Context, wc WatchConfig) <-chan *PeerLogEntry {
+	entries := make(chan *PeerLogEntry)
+	lastID := NoLogID
+	go func() {
+		defer close(entries)
+		watchLoop(ctx, wc, func(first bool) error {
+			batch, err := l.PeerLogContext(ctx, lastID)
+			if err != nil {
+				return err
+			}
+			for _, entry := range batch {
+				if entry.ID > lastID {
+					lastID = entry.ID
+				}
+				if first && !wc.EmitExisting {
+					continue
+				}
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return nil
+		})
+	}()
+	return entries
+}
+
+func (l *logApi) getForLog(ctx context.Context, action logAction, data map[string]string) ([]byte, error) {
+	resp, err := l.client.request.get(ctx, apiNameLog, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameLog, action, resp)
+}
@@ -0,0 +1,80 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMainLog(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var query string
+	srv.handle("log/main", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"id":7,"message":"qBittorrent v4.6.0 started","timestamp":1700000000,"type":1},{"id":8,"message":"disk full","timestamp":1700000001,"type":8}]`))
+	})
+	srv.handle("log/peers", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1,"ip":"10.0.0.1","timestamp":1700000000,"blocked":true,"reason":"IP filter"}]`))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	entries, err := c.MainLog(LogNormal|LogCritical, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "critical=true&info=false&last_known_id=6&normal=true&warning=false"; query != want {
+		t.Errorf("query %s, want %s", query, want)
+	}
+	if len(entries) != 2 || entries[1].Type != LogCritical || !entries[0].Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	peers, err := c.PeerLog(NoLogID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || !peers[0].Blocked || peers[0].Reason != "IP filter" || !peers[0].Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected peer log %+v", peers)
+	}
+}
+
+func TestTailMainLog(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	next := 3 // the log already contains the ids 0 to 2
+	srv.handle("log/main", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		last, _ := strconv.Atoi(r.URL.Query().Get("last_known_id"))
+		var entries []string
+		for id := last + 1; id < next; id++ {
+			entries = append(entries, fmt.Sprintf(`{"id":%d,"message":"line %d","timestamp":0,"type":2}`, id, id))
+		}
+		next++ // one new line per poll
+		fmt.Fprintf(w, "[%s]", strings.Join(entries, ","))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	entries := c.TailMainLog(ctx, LogAll, WatchConfig{PollInterval: time.Millisecond})
+	for want := int64(3); want < 6; want++ {
+		entry, ok := <-entries
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		if entry.ID != want {
+			t.Errorf("got id %d, want %d", entry.ID, want)
+		}
+	}
+	cancel()
+	for range entries {
+	}
+}
@@ -3,6 +3,7 @@ package magnet
 import (
 	"encoding/base32"
 	"encoding/hex"
+	"net/url"
 	"strings"
 )
 
@@ -66,3 +67,43 @@ func GetHash(src string) string {
 		return ""
 	}
 }
+
+// ParseHash returns the lowercase hex info hash of a magnet URI which may
+// carry further parameters like dn or tr. For v2-only magnets the sha256
+// hash is truncated to 40 characters, like qBittorrent does for its hash.
+// An empty string is returned if the URI has no usable hash, btih values
+// must be 40 hex or 32 base32 characters.
+func ParseHash(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "magnet" {
+		return ""
+	}
+
+	var v2 string
+	for _, xt := range u.Query()["xt"] {
+		switch {
+		case strings.HasPrefix(xt, "urn:btih:"):
+			if hash := btihHash(strings.TrimPrefix(xt, "urn:btih:")); hash != "" {
+				return hash
+			}
+		case strings.HasPrefix(xt, "urn:btmh:1220") && len(xt) == 13+64:
+			if _, err := hex.DecodeString(xt[13:]); err == nil {
+				v2 = strings.ToLower(xt[13 : 13+40])
+			}
+		}
+	}
+	return v2
+}
+
+// btihHash validates a v1 info hash and returns it as lowercase hex.
+func btihHash(src string) string {
+	switch len(src) {
+	case 40:
+		if _, err := hex.DecodeString(src); err == nil {
+			return strings.ToLower(src)
+		}
+	case 32:
+		return base32ToHex(strings.ToUpper(src))
+	}
+	return ""
+}
@@ -116,3 +116,62 @@ func TestGetHash(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHash(t *testing.T) {
+	type args struct {
+		uri string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "hex with params",
+			args: args{"magnet:?xt=urn:btih:B7092DA3A99DD0FA5A701EBE2A6DCB897A6E50AA&dn=name&tr=udp%3A%2F%2Ftracker"},
+			want: "b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aa",
+		},
+		{
+			name: "base32",
+			args: args{"magnet:?dn=name&xt=urn:btih:W4ES3I5JTXIPUWTQD27CU3OLRF5G4UFK"},
+			want: "b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aa",
+		},
+		{
+			name: "v2",
+			args: args{"magnet:?xt=urn:btmh:1220caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa9f6105232b28ad099f3a302e"},
+			want: "caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa",
+		},
+		{
+			name: "http",
+			args: args{"http://example.com/a.torrent"},
+			want: "",
+		},
+		{
+			name: "invalid hash",
+			args: args{"magnet:?xt=urn:btih:zz"},
+			want: "",
+		},
+		{
+			name: "short hex",
+			args: args{"magnet:?xt=urn:btih:b7092da3a99dd0fa"},
+			want: "",
+		},
+		{
+			name: "long hex",
+			args: args{"magnet:?xt=urn:btih:b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aab7092da3a99dd0fa5a70"},
+			want: "",
+		},
+		{
+			name: "lowercase base32",
+			args: args{"magnet:?xt=urn:btih:w4es3i5jtxipuwtqd27cu3olrf5g4ufk"},
+			want: "b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseHash(tt.args.uri); got != tt.want {
+				t.Errorf("ParseHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package qbittorrent_api
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PeerAddr is the address of a peer, an IPv4 or IPv6 address and a port.
+// String formats IPv6 addresses in brackets, e.g. "[2001:db8::1]:6881".
+type PeerAddr struct {
+	IP   netip.Addr
+	Port uint16
+}
+
+// NewPeerAddr validates ip and port and returns them as PeerAddr.
+func NewPeerAddr(ip string, port int) (PeerAddr, error) {
+	addr, err := netip.ParseAddr(strings.Trim(ip, "[]"))
+	if err != nil {
+		return PeerAddr{}, errors.Wrapf(err, "invalid peer ip %q", ip)
+	}
+	// qBittorrent can't parse addresses with a zone like fe80::1%eth0
+	if addr.Zone() != "" {
+		return PeerAddr{}, errors.Errorf("invalid peer ip %q: zones are not supported", ip)
+	}
+	if port <= 0 || port > 65535 {
+		return PeerAddr{}, errors.Errorf("invalid peer port %d", port)
+	}
+	return PeerAddr{IP: addr.Unmap(), Port: uint16(port)}, nil
+}
+
+// ParsePeerAddr parses "host:port", where IPv6 hosts are enclosed in brackets.
+func ParsePeerAddr(s string) (PeerAddr, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return PeerAddr{}, errors.Errorf("invalid peer address %q: missing port", s)
+	}
+	host := s[:i]
+	if strings.Contains(host, ":") && !(strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]")) {
+		return PeerAddr{}, errors.Errorf("invalid peer address %q: IPv6 address must be in brackets", s)
+	}
+	port, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return PeerAddr{}, errors.Wrapf(err, "invalid peer address %q", s)
+	}
+	return NewPeerAddr(host, port)
+}
+
+// Validate reports whether the address is usable.
+func (p PeerAddr) Validate() error {
+	if !p.IP.IsValid() {
+		return errors.New("invalid peer address: missing ip")
+	}
+	if p.IP.Zone() != "" {
+		return errors.Errorf("invalid peer address %s: zones are not supported", p.IP)
+	}
+	if p.Port == 0 {
+		return errors.Errorf("invalid peer address %s: missing port", p.IP)
+	}
+	return nil
+}
+
+func (p PeerAddr) String() string {
+	return netip.AddrPortFrom(p.IP.Unmap(), p.Port).String()
+}
+
+// joinPeerAddrs validates the peers and joins them with "|".
+func joinPeerAddrs(peers []PeerAddr) (string, error) {
+	addrs := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if err := peer.Validate(); err != nil {
+			return "", err
+		}
+		addrs = append(addrs, peer.String())
+	}
+	return strings.Join(addrs, "|"), nil
+}
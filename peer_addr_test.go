@@ -0,0 +1,79 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+)
+
+func TestParsePeerAddr(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.2.3.4:6881", want: "1.2.3.4:6881"},
+		{in: "[2001:db8::1]:51413", want: "[2001:db8::1]:51413"},
+		{in: "[::ffff:1.2.3.4]:80", want: "1.2.3.4:80"},
+		{in: "2001:db8::1:51413", wantErr: true},
+		{in: "1.2.3.4", wantErr: true},
+		{in: "1.2.3.4:0", wantErr: true},
+		{in: "1.2.3.4:65536", wantErr: true},
+		{in: "example.com:80", wantErr: true},
+		{in: "[fe80::1%eth0]:6881", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePeerAddr(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeerAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParsePeerAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := (PeerAddr{IP: netip.MustParseAddr("fe80::1%eth0"), Port: 6881}).Validate(); err == nil {
+		t.Error("expected an error for an address with a zone")
+	}
+	if err := (PeerAddr{}).Validate(); err == nil {
+		t.Error("zero PeerAddr is valid")
+	}
+}
+
+func TestBanAndAddPeers(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var banned, added, hashes string
+	srv.handle("transfer/banPeers", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		banned = r.PostForm.Get("peers")
+	})
+	srv.handle("torrents/addPeers", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		added, hashes = r.PostForm.Get("peers"), r.PostForm.Get("hashes")
+		w.Write([]byte(`{"aaa":{"added":2,"failed":0},"bbb":{"added":1,"failed":1}}`))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	v4, _ := NewPeerAddr("10.0.0.1", 6881)
+	v6, _ := NewPeerAddr("2001:db8::2", 6882)
+	if err := c.BanPeers(v4, v6); err != nil {
+		t.Fatal(err)
+	}
+	if banned != "10.0.0.1:6881|[2001:db8::2]:6882" {
+		t.Errorf("banned %q", banned)
+	}
+	if err := c.BanPeers(PeerAddr{}); err == nil {
+		t.Error("invalid peer was banned")
+	}
+
+	results, err := c.AddPeersByHashes([]PeerAddr{v4, v6}, []string{"aaa", "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != "10.0.0.1:6881|[2001:db8::2]:6882" || hashes != "aaa|bbb" || results["bbb"].Failed != 1 || results["aaa"].Added != 2 {
+		t.Errorf("added %q to %q, results %+v", added, hashes, results)
+	}
+}
@@ -2,21 +2,29 @@ package qbittorrent_api
 
 import (
 	"bytes"
+	"context"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/publicsuffix"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync/atomic"
 )
 
 type request struct {
 	Jar      http.CookieJar
-	http     *http.Client
+	http     atomic.Pointer[http.Client]
 	host     string
 	basePath string
+	// generation is bumped on every successful login, it tells a request
+	// that got 403 whether the session was already renewed in the meantime.
+	generation atomic.Uint64
+	// reauth logs in again after the session expired, nil disables it.
+	reauth func(ctx context.Context, generation uint64) error
 }
 
 func (r *request) initialize() {
@@ -24,71 +32,115 @@ func (r *request) initialize() {
 	// base path for all API endpoints
 	r.basePath = "api/v2"
 	r.Jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
-	r.http = &http.Client{
+	r.http.Store(&http.Client{
 		Jar: r.Jar,
-	}
+	})
 }
 
-func (r *request) get(name apiName, path string, params map[string]string) (*http.Response, error) {
-	urlStr, err := url.JoinPath(r.host, r.basePath, name, path)
+// httpClient returns the client used for all requests. The returned client
+// must not be modified, use setHTTPClient with a copy instead.
+func (r *request) httpClient() *http.Client {
+	return r.http.Load()
+}
+
+func (r *request) setHTTPClient(hc *http.Client) {
+	r.http.Store(hc)
+}
+
+// updateHTTPClient applies fn to a copy of the current client and swaps it in.
+func (r *request) updateHTTPClient(fn func(hc *http.Client)) {
+	hc := *r.httpClient()
+	fn(&hc)
+	r.setHTTPClient(&hc)
+}
+
+// clearCookies expires the session cookies in place, so the jar and the
+// client can keep being used by concurrent requests.
+func (r *request) clearCookies() {
+	u, err := url.Parse(r.host)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate url")
+		return
 	}
-	req, err := http.NewRequest("GET", urlStr, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to build request")
+	var expired []*http.Cookie
+	for _, cookie := range r.Jar.Cookies(u) {
+		expired = append(expired, &http.Cookie{Name: cookie.Name, Path: "/", MaxAge: -1})
 	}
+	if len(expired) > 0 {
+		r.Jar.SetCookies(u, expired)
+	}
+}
 
-	// add user-agent header to allow qbittorrent to identify us
-	req.Header.Set("User-Agent", "go-qbittorrent "+GoQBitVersion)
-
-	// add optional parameters that the user wants
-	if params != nil {
-		query := req.URL.Query()
-		for k, v := range params {
-			query.Add(k, v)
+// transport returns a private copy of the client's transport so that
+// TLS and proxy settings can be changed without touching shared state.
+func (r *request) transport() *http.Transport {
+	var t *http.Transport
+	r.updateHTTPClient(func(hc *http.Client) {
+		switch rt := hc.Transport.(type) {
+		case *http.Transport:
+			t = rt.Clone()
+		default:
+			t = http.DefaultTransport.(*http.Transport).Clone()
 		}
-		req.URL.RawQuery = query.Encode()
-	}
+		hc.Transport = t
+	})
+	return t
+}
 
-	resp, err := r.http.Do(req)
+// do builds and performs a request. When qBittorrent answers 403 because the
+// session expired, it logs in again and replays the request once, so build
+// must return a fresh request on every call.
+func (r *request) do(ctx context.Context, name apiName, build func() (*http.Request, error)) (*http.Response, error) {
+	generation := r.generation.Load()
+	resp, err := r.send(build)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to perform request")
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusForbidden || name == apiNameAuthorization || r.reauth == nil {
+		return resp, nil
+	}
+	if expired, err := sessionExpired(resp); err != nil || !expired {
+		return resp, err
 	}
 
-	return resp, nil
+	if err := r.reauth(ctx, generation); err != nil {
+		if errors.Is(err, errNoCredentials) {
+			return resp, nil
+		}
+		resp.Body.Close()
+		return nil, errors.Wrap(err, "failed to re-authenticate")
+	}
+	resp.Body.Close()
 
+	logrus.Debug("session renewed, replaying request")
+	return r.send(build)
 }
 
-func (r *request) post(name apiName, path string, params map[string]string) (*http.Response, error) {
-	urlStr, err := url.JoinPath(r.host, r.basePath, name, path)
+// sessionExpired tells a 403 for a missing session apart from one rejecting
+// the request itself, like setLocation to a path that is not writable. The
+// WebUI answers the former with a bare "Forbidden" and explains the latter.
+// The body is buffered so the response can still be read.
+func sessionExpired(resp *http.Response) (bool, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate url")
+		return false, errors.Wrap(err, "failed to read response")
 	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
 
-	form := url.Values{}
-	if params != nil {
-		for k, v := range params {
-			form.Add(k, v)
-		}
-	}
+	msg := strings.TrimSpace(string(body))
+	return msg == "" || msg == http.StatusText(http.StatusForbidden), nil
+}
 
-	req, err := http.NewRequest("POST", urlStr, strings.NewReader(form.Encode()))
+func (r *request) send(build func() (*http.Request, error)) (*http.Response, error) {
+	req, err := build()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build request")
 	}
 
-	// add the content-type so qbittorrent knows what to expect
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	// add user-agent header to allow qbittorrent to identify us
 	req.Header.Set("User-Agent", "go-qbittorrent "+GoQBitVersion)
-	//req.Header.Set("X-Requested-With", "XMLHttpRequest")
-	//req.Header.Set("Host", "nas.zerotier.xyt:8999")
-	//req.Header.Set("Origin", "http://nas.zerotier.xyt:8999")
 
-	// add optional parameters that the user wants
-
-	resp, err := r.http.Do(req)
+	resp, err := r.httpClient().Do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to perform request")
 	}
@@ -96,27 +148,87 @@ func (r *request) post(name apiName, path string, params map[string]string) (*ht
 	return resp, nil
 }
 
-func (r *request) postMultipart(urlStr string, buffer bytes.Buffer, contentType string) (*http.Response, error) {
+// readBody reads and closes the response body. Error status codes are
+// returned as *APIError.
+func readBody(name apiName, action string, resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	if err := newAPIError(name, action, resp, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
 
-	req, err := http.NewRequest("POST", urlStr, &buffer)
+func (r *request) get(ctx context.Context, name apiName, path string, params map[string]string) (*http.Response, error) {
+	urlStr, err := url.JoinPath(r.host, r.basePath, name, path)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to build request")
+		return nil, errors.Wrap(err, "failed to generate url")
 	}
 
-	// add the content-type so qbittorrent knows what to expect
-	req.Header.Set("Content-Type", contentType)
-	// add user-agent header to allow qbittorrent to identify us
-	req.Header.Set("User-Agent", "go-qbittorrent "+GoQBitVersion)
+	return r.do(ctx, name, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// add optional parameters that the user wants
+		if params != nil {
+			query := req.URL.Query()
+			for k, v := range params {
+				query.Add(k, v)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+		return req, nil
+	})
+}
 
-	resp, err := r.http.Do(req)
+func (r *request) post(ctx context.Context, name apiName, path string, params map[string]string) (*http.Response, error) {
+	urlStr, err := url.JoinPath(r.host, r.basePath, name, path)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to perform request")
+		return nil, errors.Wrap(err, "failed to generate url")
 	}
 
-	return resp, nil
+	form := url.Values{}
+	if params != nil {
+		for k, v := range params {
+			form.Add(k, v)
+		}
+	}
+	encoded := form.Encode()
+
+	return r.do(ctx, name, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", urlStr, strings.NewReader(encoded))
+		if err != nil {
+			return nil, err
+		}
+
+		// add the content-type so qbittorrent knows what to expect
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		//req.Header.Set("X-Requested-With", "XMLHttpRequest")
+		//req.Header.Set("Host", "nas.zerotier.xyt:8999")
+		//req.Header.Set("Origin", "http://nas.zerotier.xyt:8999")
+		return req, nil
+	})
 }
 
-func (r *request) postMultipartData(name apiName, path string, params map[string]string) (*http.Response, error) {
+func (r *request) postMultipart(ctx context.Context, name apiName, urlStr string, body []byte, contentType string) (*http.Response, error) {
+	return r.do(ctx, name, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", urlStr, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		// add the content-type so qbittorrent knows what to expect
+		req.Header.Set("Content-Type", contentType)
+		return req, nil
+	})
+}
+
+func (r *request) postMultipartData(ctx context.Context, name apiName, path string, params map[string]string) (*http.Response, error) {
 	var buffer bytes.Buffer
 	writer := multipart.NewWriter(&buffer)
 	for key, val := range params {
@@ -132,43 +244,121 @@ func (r *request) postMultipartData(name apiName, path string, params map[string
 		return nil, errors.Wrap(err, "failed to generate url")
 	}
 
-	return r.postMultipart(urlStr, buffer, writer.FormDataContentType())
-}
-
-func (r *request) postMultipartFile(name apiName, urlPath, fileName string, params map[string]string) (*http.Response, error) {
-	//var buffer bytes.Buffer
-	//writer := multipart.NewWriter(&buffer)
-	//
-	//// open the file for reading
-	//file, err := os.Open(fileName)
-	//if err != nil {
-	//	return nil, errors.Wrap(err, "error opening file")
-	//}
-	//
-	//// create form for writing the file to and give it the filename
-	//formWriter, err := writer.CreateFormFile("torrents", path.Base(fileName))
-	//if err != nil {
-	//	return nil, errors.Wrap(err, "error adding file")
-	//}
-	//
-	//for key, val := range params {
-	//	writer.WriteField(key, val)
-	//}
-	//
-	//// copy the file contents into the form
-	//if _, err = io.Copy(formWriter, file); err != nil {
-	//	return nil, errors.Wrap(err, "error copying file")
-	//}
-	//
-	//if err := writer.Close(); err != nil {
-	//	return nil, errors.Wrap(err, "failed to close writer")
-	//}
-	//
-	//urlStr, err := url.JoinPath(r.host, r.basePath, name, urlPath)
-	//if err != nil {
-	//	return nil, errors.Wrap(err, "failed to generate url")
-	//}
-	//
-	//return r.postMultipart(urlStr, buffer, writer.FormDataContentType())
-	return nil, errors.New("not implement")
+	return r.postMultipart(ctx, name, urlStr, buffer.Bytes(), writer.FormDataContentType())
+}
+
+// postMultipartFile uploads torrent files together with params. The body is
+// streamed from the sources instead of being buffered in memory, except for
+// readers which can't seek, see replayableUploads.
+func (r *request) postMultipartFile(ctx context.Context, name apiName, urlPath string, files []TorrentUpload, params map[string]string) (*http.Response, error) {
+	urlStr, err := url.JoinPath(r.host, r.basePath, name, urlPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate url")
+	}
+
+	files, err = replayableUploads(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// the writer of a previous attempt must be done with the sources before
+	// they are rewound for a replay
+	var prevBody *io.PipeReader
+	var prevDone chan struct{}
+	build := func() (*http.Request, error) {
+		if prevBody != nil {
+			prevBody.CloseWithError(errors.New("request replayed"))
+			<-prevDone
+		}
+
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		req, err := http.NewRequestWithContext(ctx, "POST", urlStr, pr)
+		if err != nil {
+			return nil, err
+		}
+		// the WebUI expects a Content-Length, it can only be left out
+		// when one of the readers has an unknown size
+		if length, ok := multipartLength(writer.Boundary(), files, params); ok {
+			req.ContentLength = length
+		}
+		// add the content-type so qbittorrent knows what to expect
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		// the transport closes the body when it fails, which stops the writer
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			pw.CloseWithError(writeMultipartFiles(writer, files, params))
+		}()
+		prevBody, prevDone = pr, done
+		return req, nil
+	}
+	return r.do(ctx, name, build)
+}
+
+func writeMultipartFiles(writer *multipart.Writer, files []TorrentUpload, params map[string]string) error {
+	for i, file := range files {
+		part, err := writer.CreateFormFile("torrents", file.fileName(i))
+		if err != nil {
+			return errors.Wrap(err, "error adding file")
+		}
+		src, err := file.open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, src)
+		src.Close()
+		if err != nil {
+			return errors.Wrap(err, "error copying file")
+		}
+	}
+
+	for key, val := range params {
+		if err := writer.WriteField(key, val); err != nil {
+			return errors.Wrap(err, "error adding field")
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "failed to close writer")
+	}
+	return nil
+}
+
+// multipartLength computes the size of the body written by
+// writeMultipartFiles without reading the torrent contents.
+func multipartLength(boundary string, files []TorrentUpload, params map[string]string) (int64, bool) {
+	var counter countingWriter
+	writer := multipart.NewWriter(&counter)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return 0, false
+	}
+
+	var size int64
+	for i, file := range files {
+		n, ok := file.size()
+		if !ok {
+			return 0, false
+		}
+		size += n
+		if _, err := writer.CreateFormFile("torrents", file.fileName(i)); err != nil {
+			return 0, false
+		}
+	}
+	for key, val := range params {
+		writer.WriteField(key, val)
+	}
+	writer.Close()
+
+	return size + counter.n, true
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
 }
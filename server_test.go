@@ -0,0 +1,88 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// fakeServer is a minimal stand-in for the qBittorrent WebUI. It checks the
+// SID cookie on every endpoint except auth/login and serves the registered
+// handlers by "<apiName>/<action>".
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sid      string
+	handlers map[string]http.HandlerFunc
+	logins   int32
+}
+
+func newFakeServer() *fakeServer {
+	return newFakeServerWithVersion("2.8.19")
+}
+
+// newFakeServerWithVersion emulates a server with the given Web API version.
+func newFakeServerWithVersion(version string) *fakeServer {
+	s := &fakeServer{handlers: map[string]http.HandlerFunc{}}
+	s.handlers["app/webapiVersion"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(version))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *fakeServer) handle(path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[path] = h
+}
+
+// expire drops the current session like the WebUI session timeout does.
+func (s *fakeServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sid = ""
+}
+
+func (s *fakeServer) loginCount() int {
+	return int(atomic.LoadInt32(&s.logins))
+}
+
+func (s *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v2/")
+	switch path {
+	case "auth/login":
+		r.ParseForm()
+		if r.PostForm.Get("username") != name || r.PostForm.Get("password") != pwd {
+			w.Write([]byte("Fails."))
+			return
+		}
+		n := atomic.AddInt32(&s.logins, 1)
+		s.mu.Lock()
+		s.sid = "sid" + strconv.Itoa(int(n))
+		s.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid" + strconv.Itoa(int(n)), Path: "/"})
+		w.Write([]byte("Ok."))
+		return
+	case "auth/logout":
+		s.expire()
+		return
+	}
+
+	s.mu.Lock()
+	sid, h := s.sid, s.handlers[path]
+	s.mu.Unlock()
+	if cookie, err := r.Cookie("SID"); err != nil || sid == "" || cookie.Value != sid {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h(w, r)
+}
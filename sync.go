@@ -0,0 +1,358 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type syncApi struct {
+	client *Client
+}
+
+type syncAction = string
+
+const (
+	actionMainData syncAction = "maindata"
+)
+
+// ServerState is the global transfer and session state in sync/maindata.
+type ServerState struct {
+	AllTimeDl            int64            `json:"alltime_dl"`             // Bytes downloaded since the first start
+	AllTimeUl            int64            `json:"alltime_ul"`             // Bytes uploaded since the first start
+	AverageTimeQueue     int              `json:"average_time_queue"`     // ms
+	ConnectionStatus     ConnectionStatus `json:"connection_status"`      // connected, firewalled or disconnected
+	DHTNodes             int              `json:"dht_nodes"`              // DHT nodes connected to
+	DlInfoData           int64            `json:"dl_info_data"`           // Bytes downloaded this session
+	DlInfoSpeed          int64            `json:"dl_info_speed"`          // Global download rate (bytes/s)
+	DlRateLimit          int64            `json:"dl_rate_limit"`          // Download rate limit (bytes/s)
+	FreeSpaceOnDisk      int64            `json:"free_space_on_disk"`     // Bytes free in the default save path
+	GlobalRatio          string           `json:"global_ratio"`           // e.g. "1.23"
+	QueuedIOJobs         int              `json:"queued_io_jobs"`         // Disk jobs waiting
+	Queueing             bool             `json:"queueing"`               // True if torrent queueing is enabled
+	ReadCacheHits        string           `json:"read_cache_hits"`        // percent
+	ReadCacheOverload    string           `json:"read_cache_overload"`    // percent
+	RefreshInterval      int              `json:"refresh_interval"`       // ms, WebUI refresh interval
+	TotalBuffersSize     int64            `json:"total_buffers_size"`     // bytes
+	TotalPeerConnections int              `json:"total_peer_connections"` // Connected peers
+	TotalQueuedSize      int64            `json:"total_queued_size"`      // bytes
+	TotalWastedSession   int64            `json:"total_wasted_session"`   // Bytes wasted this session
+	UpInfoData           int64            `json:"up_info_data"`           // Bytes uploaded this session
+	UpInfoSpeed          int64            `json:"up_info_speed"`          // Global upload rate (bytes/s)
+	UpRateLimit          int64            `json:"up_rate_limit"`          // Upload rate limit (bytes/s)
+	UseAltSpeedLimits    bool             `json:"use_alt_speed_limits"`   // True if alternative speed limits are enabled
+	WriteCacheOverload   string           `json:"write_cache_overload"`   // percent
+	LastExternalAddrV4   string           `json:"last_external_address_v4"`
+	LastExternalAddrV6   string           `json:"last_external_address_v6"`
+}
+
+// mainData is a response of sync/maindata. Objects only carry the fields
+// which changed since rid, unless FullUpdate is set.
+type mainData struct {
+	Rid               int64                      `json:"rid"`
+	FullUpdate        bool                       `json:"full_update"`
+	Torrents          map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved   []string                   `json:"torrents_removed"`
+	Categories        map[string]json.RawMessage `json:"categories"`
+	CategoriesRemoved []string                   `json:"categories_removed"`
+	Tags              []string                   `json:"tags"`
+	TagsRemoved       []string                   `json:"tags_removed"`
+	Trackers          map[string][]string        `json:"trackers"`
+	TrackersRemoved   []string                   `json:"trackers_removed"`
+	ServerState       json.RawMessage            `json:"server_state"`
+}
+
+func (s *syncApi) mainData(ctx context.Context, rid int64) (*mainData, error) {
+	body, err := s.getForSync(ctx, actionMainData, map[string]string{"rid": strconv.FormatInt(rid, 10)})
+	if err != nil {
+		return nil, err
+	}
+
+	var data mainData
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (s *syncApi) getForSync(ctx context.Context, action syncAction, data map[string]string) ([]byte, error) {
+	resp, err := s.client.request.get(ctx, apiNameSync, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameSync, action, resp)
+}
+
+// MainDataMirror keeps a local copy of the qBittorrent state, which is
+// updated incrementally from sync/maindata. It is safe for concurrent use.
+type MainDataMirror struct {
+	client *Client
+
+	// updateMu serializes Update, so that every delta is applied on top
+	// of the rid it was requested for
+	updateMu sync.Mutex
+
+	mu          sync.RWMutex
+	rid         int64
+	torrents    map[string]*Torrent
+	categories  map[string]*Category
+	tags        map[string]struct{}
+	trackers    map[string][]string
+	serverState ServerState
+}
+
+// NewMainDataMirror returns an empty mirror, the first Update fetches the
+// complete state and later calls only the changes.
+func (s *syncApi) NewMainDataMirror() *MainDataMirror {
+	m := &MainDataMirror{client: s.client}
+	m.reset()
+	return m
+}
+
+func (m *MainDataMirror) reset() {
+	m.torrents = map[string]*Torrent{}
+	m.categories = map[string]*Category{}
+	m.tags = map[string]struct{}{}
+	m.trackers = map[string][]string{}
+	m.serverState = ServerState{}
+}
+
+// Update fetches the changes since the last update and applies them.
+func (m *MainDataMirror) Update() error {
+	return m.UpdateContext(context.Background())
+}
+
+// UpdateContext is like Update but carries ctx for cancellation.
+func (m *MainDataMirror) UpdateContext(ctx context.Context) error {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	data, err := m.client.mainData(ctx, m.Rid())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err = m.apply(data)
+	return err
+}
+
+// updateEvents is like UpdateContext, and also returns the torrent changes.
+func (m *MainDataMirror) updateEvents(ctx context.Context) ([]TorrentEvent, error) {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	data, err := m.client.mainData(ctx, m.Rid())
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(data)
+}
+
+// apply merges a sync/maindata response into the mirror and returns the
+// resulting torrent changes. m.mu must be held.
+func (m *MainDataMirror) apply(data *mainData) ([]TorrentEvent, error) {
+	// decode everything before touching the mirror, so a response which
+	// fails half way leaves it at the last rid
+	base := m
+	if data.FullUpdate {
+		base = &MainDataMirror{}
+		base.reset()
+	}
+
+	torrents := make(map[string]*Torrent, len(data.Torrents))
+	for hash, raw := range data.Torrents {
+		torrent := Torrent{}
+		if old, ok := base.torrents[hash]; ok {
+			torrent = *old
+		}
+		if err := json.Unmarshal(raw, &torrent); err != nil {
+			return nil, errors.Wrapf(err, "torrent %s", hash)
+		}
+		torrent.Hash = hash
+		torrents[hash] = &torrent
+	}
+
+	categories := make(map[string]*Category, len(data.Categories))
+	for name, raw := range data.Categories {
+		category := Category{}
+		if old, ok := base.categories[name]; ok {
+			category = *old
+		}
+		if err := json.Unmarshal(raw, &category); err != nil {
+			return nil, errors.Wrapf(err, "category %s", name)
+		}
+		category.Name = name
+		categories[name] = &category
+	}
+
+	serverState := base.serverState
+	if len(data.ServerState) > 0 {
+		if err := json.Unmarshal(data.ServerState, &serverState); err != nil {
+			return nil, errors.Wrap(err, "server state")
+		}
+	}
+
+	// torrents are replaced, never modified in place, so the old pointers
+	// stay valid for diffing
+	previous := make(map[string]*Torrent, len(m.torrents))
+	for hash, torrent := range m.torrents {
+		previous[hash] = torrent
+	}
+
+	if data.FullUpdate {
+		m.reset()
+	}
+
+	for hash, torrent := range torrents {
+		m.torrents[hash] = torrent
+	}
+	for _, hash := range data.TorrentsRemoved {
+		delete(m.torrents, hash)
+	}
+
+	for name, category := range categories {
+		m.categories[name] = category
+	}
+	for _, name := range data.CategoriesRemoved {
+		delete(m.categories, name)
+	}
+
+	for _, tag := range data.Tags {
+		m.tags[tag] = struct{}{}
+	}
+	for _, tag := range data.TagsRemoved {
+		delete(m.tags, tag)
+	}
+
+	for url, hashes := range data.Trackers {
+		m.trackers[url] = hashes
+	}
+	for _, url := range data.TrackersRemoved {
+		delete(m.trackers, url)
+	}
+
+	m.serverState = serverState
+	m.rid = data.Rid
+	return m.diff(previous, data), nil
+}
+
+// diff compares the torrents touched by data against previous.
+func (m *MainDataMirror) diff(previous map[string]*Torrent, data *mainData) []TorrentEvent {
+	touched := map[string]bool{}
+	for hash := range data.Torrents {
+		touched[hash] = true
+	}
+	for _, hash := range data.TorrentsRemoved {
+		touched[hash] = true
+	}
+	if data.FullUpdate {
+		for hash := range previous {
+			touched[hash] = true
+		}
+	}
+	hashes := make([]string, 0, len(touched))
+	for hash := range touched {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	var events []TorrentEvent
+	for _, hash := range hashes {
+		events = append(events, torrentEvents(hash, previous[hash], m.torrents[hash])...)
+	}
+	return events
+}
+
+// Rid returns the response id of the last applied update.
+func (m *MainDataMirror) Rid() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rid
+}
+
+// Torrents returns copies of all torrents, sorted by hash.
+func (m *MainDataMirror) Torrents() []*Torrent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	torrents := make([]*Torrent, 0, len(m.torrents))
+	for _, torrent := range m.torrents {
+		t := *torrent
+		torrents = append(torrents, &t)
+	}
+	sort.Slice(torrents, func(i, j int) bool {
+		return torrents[i].Hash < torrents[j].Hash
+	})
+	return torrents
+}
+
+// Torrent returns a copy of the torrent with hash.
+func (m *MainDataMirror) Torrent(hash string) (*Torrent, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	torrent, ok := m.torrents[hash]
+	if !ok {
+		return nil, false
+	}
+	t := *torrent
+	return &t, true
+}
+
+// Categories returns copies of all categories, sorted by name.
+func (m *MainDataMirror) Categories() []*Category {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	categories := make([]*Category, 0, len(m.categories))
+	for _, category := range m.categories {
+		c := *category
+		categories = append(categories, &c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// Tags returns all tags, sorted.
+func (m *MainDataMirror) Tags() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tags := make([]string, 0, len(m.tags))
+	for tag := range m.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Trackers returns the hashes of the torrents using each tracker url.
+// Only servers since qBittorrent 4.5 report trackers.
+func (m *MainDataMirror) Trackers() map[string][]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	trackers := make(map[string][]string, len(m.trackers))
+	for url, hashes := range m.trackers {
+		trackers[url] = append([]string(nil), hashes...)
+	}
+	return trackers
+}
+
+// ServerState returns the global server state.
+func (m *MainDataMirror) ServerState() ServerState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serverState
+}
@@ -0,0 +1,171 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+const (
+	actionTorrentPeers syncAction = "torrentPeers"
+)
+
+// Peer is a peer connected for a torrent, see sync/torrentPeers.
+type Peer struct {
+	IP           string  `json:"ip"`
+	Port         int     `json:"port"`
+	Client       string  `json:"client"`         // Client name and version
+	PeerIDClient string  `json:"peer_id_client"` // Client derived from the peer id
+	Connection   string  `json:"connection"`     // BT, uTP or Web
+	Country      string  `json:"country"`
+	CountryCode  string  `json:"country_code"`
+	Flags        string  `json:"flags"`      // e.g. "D X E P"
+	FlagsDesc    string  `json:"flags_desc"` // Description of each flag, one per line
+	Progress     float64 `json:"progress"`   // Progress of the peer (percentage/100)
+	Relevance    float64 `json:"relevance"`  // Share of the pieces we miss the peer has (percentage/100)
+	DlSpeed      int64   `json:"dl_speed"`   // bytes/s
+	UpSpeed      int64   `json:"up_speed"`   // bytes/s
+	Downloaded   int64   `json:"downloaded"` // Bytes downloaded from the peer
+	Uploaded     int64   `json:"uploaded"`   // Bytes uploaded to the peer
+	Files        string  `json:"files"`      // Files the peer is currently transferring, one per line
+}
+
+// torrentPeers is a response of sync/torrentPeers. Peers only carry the
+// fields which changed since rid, unless FullUpdate is set.
+type torrentPeers struct {
+	Rid          int64                      `json:"rid"`
+	FullUpdate   bool                       `json:"full_update"`
+	Peers        map[string]json.RawMessage `json:"peers"`
+	PeersRemoved []string                   `json:"peers_removed"`
+	ShowFlags    bool                       `json:"show_flags"`
+}
+
+func (s *syncApi) torrentPeers(ctx context.Context, hash string, rid int64) (*torrentPeers, error) {
+	body, err := s.getForSync(ctx, actionTorrentPeers, map[string]string{
+		"hash": hash,
+		"rid":  strconv.FormatInt(rid, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var data torrentPeers
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// TorrentPeers returns the peers of the torrent with hash, keyed by "ip:port".
+// To poll the peers repeatedly use NewPeerMirror, which only fetches changes.
+func (s *syncApi) TorrentPeers(hash string) (map[string]*Peer, error) {
+	return s.TorrentPeersContext(context.Background(), hash)
+}
+
+// TorrentPeersContext is like TorrentPeers but carries ctx for cancellation.
+func (s *syncApi) TorrentPeersContext(ctx context.Context, hash string) (map[string]*Peer, error) {
+	m := s.NewPeerMirror(hash)
+	if err := m.UpdateContext(ctx); err != nil {
+		return nil, err
+	}
+	return m.Peers(), nil
+}
+
+// PeerMirror keeps a local copy of the peers of one torrent, which is
+// updated incrementally from sync/torrentPeers. It is safe for concurrent use.
+type PeerMirror struct {
+	client *Client
+	hash   string
+
+	updateMu sync.Mutex
+
+	mu    sync.RWMutex
+	rid   int64
+	peers map[string]*Peer
+}
+
+// NewPeerMirror returns an empty peer mirror for the torrent with hash.
+func (s *syncApi) NewPeerMirror(hash string) *PeerMirror {
+	return &PeerMirror{client: s.client, hash: hash, peers: map[string]*Peer{}}
+}
+
+// Hash returns the hash of the mirrored torrent.
+func (m *PeerMirror) Hash() string {
+	return m.hash
+}
+
+// Update fetches the peer changes since the last update and applies them.
+func (m *PeerMirror) Update() error {
+	return m.UpdateContext(context.Background())
+}
+
+// UpdateContext is like Update but carries ctx for cancellation.
+func (m *PeerMirror) UpdateContext(ctx context.Context) error {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	data, err := m.client.torrentPeers(ctx, m.hash, m.Rid())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(data)
+}
+
+// apply merges a sync/torrentPeers response into the mirror. m.mu must be held.
+func (m *PeerMirror) apply(data *torrentPeers) error {
+	if data.FullUpdate {
+		m.peers = map[string]*Peer{}
+	}
+	for addr, raw := range data.Peers {
+		peer := Peer{}
+		if old, ok := m.peers[addr]; ok {
+			peer = *old
+		}
+		if err := json.Unmarshal(raw, &peer); err != nil {
+			return err
+		}
+		m.peers[addr] = &peer
+	}
+	for _, addr := range data.PeersRemoved {
+		delete(m.peers, addr)
+	}
+	m.rid = data.Rid
+	return nil
+}
+
+// Rid returns the response id of the last applied update.
+func (m *PeerMirror) Rid() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rid
+}
+
+// Peers returns copies of all peers, keyed by "ip:port".
+func (m *PeerMirror) Peers() map[string]*Peer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	peers := make(map[string]*Peer, len(m.peers))
+	for addr, peer := range m.peers {
+		p := *peer
+		peers[addr] = &p
+	}
+	return peers
+}
+
+// Peer returns a copy of the peer with addr "ip:port".
+func (m *PeerMirror) Peer(addr string) (*Peer, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	peer, ok := m.peers[addr]
+	if !ok {
+		return nil, false
+	}
+	p := *peer
+	return &p, true
+}
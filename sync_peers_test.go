@@ -0,0 +1,52 @@
+package qbittorrent_api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPeerMirror(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,"show_flags":true,"peers":{
+			"1.2.3.4:6881":{"ip":"1.2.3.4","port":6881,"client":"qBittorrent 4.6.0","country_code":"de","progress":0.25,"dl_speed":100,"relevance":0.5,"files":"a.mkv"},
+			"[::1]:6881":{"ip":"::1","port":6881,"client":"Transmission 4.0"}}}`,
+		"1": `{"rid":2,"peers":{"1.2.3.4:6881":{"progress":0.5,"dl_speed":0}},"peers_removed":["[::1]:6881"]}`,
+	}
+	srv.handle("sync/torrentPeers", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("hash") != "aaa" {
+			http.Error(w, "Torrent hash was not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(responses[r.URL.Query().Get("rid")]))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	peers, err := c.TorrentPeers("aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 2 || peers["1.2.3.4:6881"].Client != "qBittorrent 4.6.0" || peers["[::1]:6881"].IP != "::1" {
+		t.Errorf("unexpected peers %+v", peers)
+	}
+
+	m := c.NewPeerMirror("aaa")
+	for i := 0; i < 2; i++ {
+		if err := m.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, ok := m.Peer("1.2.3.4:6881")
+	if !ok || p.Progress != 0.5 || p.DlSpeed != 0 || p.CountryCode != "de" || p.Files != "a.mkv" {
+		t.Errorf("unexpected peer %+v", p)
+	}
+	if len(m.Peers()) != 1 || m.Rid() != 2 {
+		t.Errorf("unexpected peers %+v at rid %d", m.Peers(), m.Rid())
+	}
+
+	if _, err := c.TorrentPeers("bbb"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMainDataMirror(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,
+			"torrents":{"aaa":{"name":"a","state":"downloading","progress":0.5,"max_ratio":1.5},"bbb":{"name":"b","state":"uploading"}},
+			"categories":{"movies":{"name":"movies","savePath":"/movies"}},
+			"tags":["x","y"],
+			"server_state":{"connection_status":"connected","dl_info_speed":100,"dht_nodes":10}}`,
+		"1": `{"rid":2,
+			"torrents":{"aaa":{"state":"uploading","progress":1}},
+			"torrents_removed":["bbb"],
+			"tags_removed":["y"],
+			"server_state":{"dl_info_speed":0}}`,
+	}
+	var rids []string
+	srv.handle("sync/maindata", func(w http.ResponseWriter, r *http.Request) {
+		rid := r.URL.Query().Get("rid")
+		rids = append(rids, rid)
+		resp, ok := responses[rid]
+		if !ok {
+			resp = `{"rid":3,"full_update":true,"torrents":{"ccc":{"name":"c"}}}`
+		}
+		w.Write([]byte(resp))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	m := c.NewMainDataMirror()
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if torrents := m.Torrents(); len(torrents) != 2 || torrents[0].Hash != "aaa" || torrents[0].MaxRatio != 1.5 {
+		t.Errorf("unexpected torrents %+v", torrents)
+	}
+	if categories := m.Categories(); len(categories) != 1 || categories[0].SavePath != "/movies" {
+		t.Errorf("unexpected categories %+v", categories)
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+	a, ok := m.Torrent("aaa")
+	if !ok || a.Name != "a" || a.State != StateUploading || a.Progress != 1 {
+		t.Errorf("unexpected torrent %+v", a)
+	}
+	if _, ok := m.Torrent("bbb"); ok {
+		t.Error("removed torrent still present")
+	}
+	if tags := m.Tags(); len(tags) != 1 || tags[0] != "x" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if s := m.ServerState(); s.ConnectionStatus != "connected" || s.DlInfoSpeed != 0 || s.DHTNodes != 10 {
+		t.Errorf("unexpected server state %+v", s)
+	}
+
+	// a full update replaces everything
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if torrents := m.Torrents(); len(torrents) != 1 || torrents[0].Hash != "ccc" || len(m.Categories()) != 0 {
+		t.Errorf("unexpected torrents after full update %+v", torrents)
+	}
+	if m.Rid() != 3 || len(rids) != 3 || rids[1] != "1" || rids[2] != "2" {
+		t.Errorf("unexpected rids %v, rid %d", rids, m.Rid())
+	}
+}
+
+func TestMainDataMirrorFailedUpdate(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("sync/maindata", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("rid") == "0" {
+			w.Write([]byte(`{"rid":1,"full_update":true,"torrents":{"aaa":{"name":"a","state":"downloading"}},"server_state":{"dl_info_speed":100}}`))
+			return
+		}
+		// the torrent decodes, the category doesn't
+		w.Write([]byte(`{"rid":2,"torrents":{"aaa":{"state":"uploading"}},"categories":{"movies":{"savePath":1}},"server_state":{"dl_info_speed":0}}`))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	m := c.NewMainDataMirror()
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Update(); err == nil {
+		t.Fatal("expected an error for an invalid category")
+	}
+	if a, ok := m.Torrent("aaa"); !ok || a.State != StateDownloading {
+		t.Errorf("torrent changed by a failed update: %+v", a)
+	}
+	if m.Rid() != 1 || m.ServerState().DlInfoSpeed != 100 || len(m.Categories()) != 0 {
+		t.Errorf("mirror changed by a failed update: rid %d, server state %+v", m.Rid(), m.ServerState())
+	}
+}
@@ -0,0 +1,165 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"time"
+)
+
+// TorrentEventType is the kind of change reported by Watch.
+type TorrentEventType int
+
+const (
+	EventTorrentAdded    TorrentEventType = iota // a torrent was added
+	EventTorrentRemoved                          // a torrent was deleted
+	EventStateChanged                            // Torrent.State changed
+	EventFinished                                // the download completed
+	EventCategoryChanged                         // Torrent.Category changed
+)
+
+func (e TorrentEventType) String() string {
+	switch e {
+	case EventTorrentAdded:
+		return "added"
+	case EventTorrentRemoved:
+		return "removed"
+	case EventStateChanged:
+		return "state changed"
+	case EventFinished:
+		return "finished"
+	case EventCategoryChanged:
+		return "category changed"
+	}
+	return "unknown"
+}
+
+// TorrentEvent is a change of a single torrent.
+type TorrentEvent struct {
+	Type     TorrentEventType
+	Hash     string
+	Torrent  *Torrent // the torrent after the change, nil for EventTorrentRemoved
+	Previous *Torrent // the torrent before the change, nil for EventTorrentAdded
+}
+
+func torrentEvents(hash string, old, new *Torrent) []TorrentEvent {
+	switch {
+	case old == nil && new == nil:
+		return nil
+	case old == nil:
+		return []TorrentEvent{{Type: EventTorrentAdded, Hash: hash, Torrent: new}}
+	case new == nil:
+		return []TorrentEvent{{Type: EventTorrentRemoved, Hash: hash, Previous: old}}
+	}
+
+	var events []TorrentEvent
+	event := func(typ TorrentEventType) {
+		events = append(events, TorrentEvent{Type: typ, Hash: hash, Torrent: new, Previous: old})
+	}
+	if old.State != new.State {
+		event(EventStateChanged)
+	}
+	if old.Progress < 1 && new.Progress >= 1 {
+		event(EventFinished)
+	}
+	if old.Category != new.Category {
+		event(EventCategoryChanged)
+	}
+	return events
+}
+
+// WatchConfig configures Watch, WatchFunc and the log tailers.
+type WatchConfig struct {
+	PollInterval time.Duration // interval between sync/maindata requests, 1s by default
+	MaxBackoff   time.Duration // upper bound of the retry delay after errors, 1 minute by default
+	EmitExisting bool          // report the state of the first poll, e.g. existing torrents as added
+	OnError      func(error)   // called for every failed update, the watcher keeps retrying
+}
+
+const (
+	defaultWatchPollInterval = time.Second
+	defaultWatchMaxBackoff   = time.Minute
+)
+
+func (wc WatchConfig) withDefaults() WatchConfig {
+	if wc.PollInterval <= 0 {
+		wc.PollInterval = defaultWatchPollInterval
+	}
+	if wc.MaxBackoff <= 0 {
+		wc.MaxBackoff = defaultWatchMaxBackoff
+	}
+	if wc.MaxBackoff < wc.PollInterval {
+		wc.MaxBackoff = wc.PollInterval
+	}
+	return wc
+}
+
+// Watch polls sync/maindata and sends torrent changes on the returned
+// channel, which is closed once ctx is done.
+func (s *syncApi) Watch(ctx context.Context, wc WatchConfig) <-chan TorrentEvent {
+	events := make(chan TorrentEvent)
+	go func() {
+		defer close(events)
+		s.WatchFunc(ctx, wc, func(event TorrentEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return events
+}
+
+// WatchFunc polls sync/maindata and calls fn for every torrent change until
+// ctx is done, then it returns ctx.Err(). Errors are passed to wc.OnError
+// and retried with an exponential backoff.
+func (s *syncApi) WatchFunc(ctx context.Context, wc WatchConfig, fn func(TorrentEvent)) error {
+	m := s.NewMainDataMirror()
+	return watchLoop(ctx, wc, func(first bool) error {
+		events, err := m.updateEvents(ctx)
+		if err != nil || (first && !wc.EmitExisting) {
+			return err
+		}
+		for _, event := range events {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fn(event)
+		}
+		return nil
+	})
+}
+
+// watchLoop calls poll every wc.PollInterval until ctx is done. first is
+// true until poll succeeded once. Failures are passed to wc.OnError and
+// retried with an exponentially growing delay up to wc.MaxBackoff.
+func watchLoop(ctx context.Context, wc WatchConfig, poll func(first bool) error) error {
+	wc = wc.withDefaults()
+	first := true
+	delay := wc.PollInterval
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := poll(first); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if wc.OnError != nil {
+				wc.OnError(err)
+			}
+			if delay *= 2; delay > wc.MaxBackoff {
+				delay = wc.MaxBackoff
+			}
+			timer.Reset(delay)
+			continue
+		}
+		delay = wc.PollInterval
+		first = false
+		timer.Reset(wc.PollInterval)
+	}
+}
@@ -0,0 +1,72 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	responses := []string{
+		`{"rid":1,"full_update":true,"torrents":{"aaa":{"name":"a","state":"downloading","progress":0.5}}}`,
+		"", // server error, retried
+		`{"rid":2,"torrents":{"aaa":{"state":"uploading","progress":1,"category":"done"},"bbb":{"name":"b","state":"metaDL"}}}`,
+		`{"rid":3,"torrents_removed":["aaa"]}`,
+	}
+	var mu sync.Mutex
+	calls := 0
+	srv.handle("sync/maindata", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if calls >= len(responses) {
+			w.Write([]byte(`{"rid":3}`))
+			return
+		}
+		resp := responses[calls]
+		calls++
+		if resp == "" {
+			http.Error(w, "busy", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(resp))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var errs int
+	events := c.Watch(ctx, WatchConfig{
+		PollInterval: time.Millisecond,
+		OnError:      func(error) { errs++ },
+	})
+
+	want := []struct {
+		typ  TorrentEventType
+		hash string
+	}{
+		{EventStateChanged, "aaa"},
+		{EventFinished, "aaa"},
+		{EventCategoryChanged, "aaa"},
+		{EventTorrentAdded, "bbb"},
+		{EventTorrentRemoved, "aaa"},
+	}
+	for _, w := range want {
+		event, ok := <-events
+		if !ok {
+			t.Fatalf("channel closed, want %v %s", w.typ, w.hash)
+		}
+		if event.Type != w.typ || event.Hash != w.hash {
+			t.Errorf("got %v %s, want %v %s", event.Type, event.Hash, w.typ, w.hash)
+		}
+	}
+	cancel()
+	for range events {
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want 1", errs)
+	}
+}
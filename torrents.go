@@ -1,9 +1,12 @@
 package qbittorrent_api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"io"
-	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -15,55 +18,57 @@ type torrentsApi struct {
 }
 
 type Torrent struct {
-	AddedOn                int          `json:"added_on"`
-	AmountLeft             int64        `json:"amount_left"`
-	AutoTmm                bool         `json:"auto_tmm"`
-	Availability           float64      `json:"availability"`
-	Category               string       `json:"category"`
-	Completed              int64        `json:"completed"`
-	CompletionOn           int          `json:"completion_on"`
-	ContentPath            string       `json:"content_path"`
-	DlLimit                int          `json:"dl_limit"`
-	DlSpeed                int          `json:"dlspeed"`
-	DownloadPath           string       `json:"download_path"`
-	Downloaded             int64        `json:"downloaded"`
-	DownloadedSession      int          `json:"downloaded_session"`
-	Eta                    int          `json:"eta"`
-	FirstLastPiecePriority bool         `json:"f_l_piece_prio"`
-	ForceStart             bool         `json:"force_start"`
-	Hash                   string       `json:"hash"`
-	InfoHashV1             string       `json:"infohash_v1"`
-	InfoHashV2             string       `json:"infohash_v2"`
-	LastActivity           int          `json:"last_activity"`
-	MagnetUri              string       `json:"magnet_uri"`
-	MaxRatio               int          `json:"max_ratio"`
-	MaxSeedingTime         int          `json:"max_seeding_time"`
-	Name                   string       `json:"name"`
-	NumComplete            int          `json:"num_complete"`
-	NumIncomplete          int          `json:"num_incomplete"`
-	NumLeechs              int          `json:"num_leechs"`
-	NumSeeds               int          `json:"num_seeds"`
-	Priority               int          `json:"priority"`
-	Progress               float64      `json:"progress"`
-	Ratio                  float64      `json:"ratio"`
-	RatioLimit             int          `json:"ratio_limit"`
-	SavePath               string       `json:"save_path"`
-	SeedingTime            int          `json:"seeding_time"`
-	SeedingTimeLimit       int          `json:"seeding_time_limit"`
-	SeenComplete           int          `json:"seen_complete"`
-	SequentialDownload     bool         `json:"seq_dl"`
-	Size                   int64        `json:"size"`
-	State                  TorrentState `json:"state"`
-	SuperSeeding           bool         `json:"super_seeding"`
-	Tags                   string       `json:"tags"`
-	TimeActive             int          `json:"time_active"`
-	TotalSize              int64        `json:"total_size"`
-	Tracker                string       `json:"tracker"`
-	TrackersCount          int          `json:"trackers_count"`
-	UpLimit                int          `json:"up_limit"`
-	Uploaded               int64        `json:"uploaded"`
-	UploadedSession        int          `json:"uploaded_session"`
-	UpSpeed                int          `json:"upspeed"`
+	AddedOn                  int              `json:"added_on"`
+	AmountLeft               int64            `json:"amount_left"`
+	AutoTmm                  bool             `json:"auto_tmm"`
+	Availability             float64          `json:"availability"`
+	Category                 string           `json:"category"`
+	Completed                int64            `json:"completed"`
+	CompletionOn             int              `json:"completion_on"`
+	ContentPath              string           `json:"content_path"`
+	DlLimit                  int              `json:"dl_limit"`
+	DlSpeed                  int              `json:"dlspeed"`
+	DownloadPath             string           `json:"download_path"`
+	Downloaded               int64            `json:"downloaded"`
+	DownloadedSession        int              `json:"downloaded_session"`
+	Eta                      int              `json:"eta"`
+	FirstLastPiecePriority   bool             `json:"f_l_piece_prio"`
+	ForceStart               bool             `json:"force_start"`
+	Hash                     string           `json:"hash"`
+	InfoHashV1               string           `json:"infohash_v1"`
+	InfoHashV2               string           `json:"infohash_v2"`
+	LastActivity             int              `json:"last_activity"`
+	MagnetUri                string           `json:"magnet_uri"`
+	MaxRatio                 float64          `json:"max_ratio"`
+	MaxSeedingTime           int              `json:"max_seeding_time"`
+	Name                     string           `json:"name"`
+	NumComplete              int              `json:"num_complete"`
+	NumIncomplete            int              `json:"num_incomplete"`
+	NumLeechs                int              `json:"num_leechs"`
+	NumSeeds                 int              `json:"num_seeds"`
+	Priority                 int              `json:"priority"`
+	Progress                 float64          `json:"progress"`
+	Ratio                    float64          `json:"ratio"`
+	RatioLimit               RatioLimit       `json:"ratio_limit"`
+	SavePath                 string           `json:"save_path"`
+	SeedingTime              int              `json:"seeding_time"`
+	SeedingTimeLimit         SeedingTimeLimit `json:"seeding_time_limit"`
+	InactiveSeedingTimeLimit SeedingTimeLimit `json:"inactive_seeding_time_limit"`
+	MaxInactiveSeedingTime   int              `json:"max_inactive_seeding_time"`
+	SeenComplete             int              `json:"seen_complete"`
+	SequentialDownload       bool             `json:"seq_dl"`
+	Size                     int64            `json:"size"`
+	State                    TorrentState     `json:"state"`
+	SuperSeeding             bool             `json:"super_seeding"`
+	Tags                     string           `json:"tags"`
+	TimeActive               int              `json:"time_active"`
+	TotalSize                int64            `json:"total_size"`
+	Tracker                  string           `json:"tracker"`
+	TrackersCount            int              `json:"trackers_count"`
+	UpLimit                  int              `json:"up_limit"`
+	Uploaded                 int64            `json:"uploaded"`
+	UploadedSession          int              `json:"uploaded_session"`
+	UpSpeed                  int              `json:"upspeed"`
 }
 
 type TorrentState = string
@@ -73,6 +78,7 @@ const (
 	StateMissingFiles           TorrentState = "missingFiles"
 	StateUploading              TorrentState = "uploading"
 	StatePausedUpload           TorrentState = "pausedUP"
+	StateStoppedUpload          TorrentState = "stoppedUP" // pausedUP since qBittorrent 5
 	StateQueuedUpload           TorrentState = "queuedUP"
 	StateStalledUpload          TorrentState = "stalledUP"
 	StateCheckingUpload         TorrentState = "checkingUP"
@@ -82,6 +88,7 @@ const (
 	StateMetadataDownload       TorrentState = "metaDL"
 	StateForcedMetadataDownload TorrentState = "forcedMetaDL"
 	StatePausedDownload         TorrentState = "pausedDL"
+	StateStoppedDownload        TorrentState = "stoppedDL" // pausedDL since qBittorrent 5
 	StateQueuedDownload         TorrentState = "queuedDL"
 	StateForcedDownload         TorrentState = "forcedDL"
 	StateStalledDownload        TorrentState = "stalledDL"
@@ -99,6 +106,7 @@ func (t Torrent) IsDownloading() bool {
 		StateStalledDownload,
 		StateCheckingDownload,
 		StatePausedDownload,
+		StateStoppedDownload,
 		StateQueuedDownload,
 		StateForcedDownload:
 		return true
@@ -124,6 +132,7 @@ func (t Torrent) IsComplete() bool {
 		StateStalledUpload,
 		StateCheckingUpload,
 		StatePausedUpload,
+		StateStoppedUpload,
 		StateQueuedUpload,
 		StateForcedUpload:
 		return true
@@ -141,14 +150,23 @@ func (t Torrent) IsErrored() bool {
 
 }
 
+// IsPaused reports whether the torrent is paused, or stopped as qBittorrent 5 calls it.
 func (t Torrent) IsPaused() bool {
-	return t.State == StatePausedUpload || t.State == StatePausedDownload
+	switch t.State {
+	case StatePausedUpload,
+		StatePausedDownload,
+		StateStoppedUpload,
+		StateStoppedDownload:
+		return true
+	}
+	return false
 }
 
 // Filter
 // param status_filter: Filter list by all, downloading, completed, paused, active, inactive, resumed
 //
 //	stalled, stalled_uploading and stalled_downloading added in Web API 2.4.1
+//	stopped and running replace paused and resumed in Web API 2.11.0, both names work with any server
 //
 // param category: Filter list by category
 // param sort: Sort list by any property returned
@@ -176,6 +194,19 @@ const (
 	FilterSortProgress = "progress"
 )
 
+// statusFilterRenames translates the status filters renamed by qBittorrent 5,
+// so both names work with every server. renamed tells on which side of the
+// rename the server must be for the filter to be replaced by other.
+var statusFilterRenames = map[string]struct {
+	renamed bool
+	other   string
+}{
+	"paused":  {renamed: true, other: "stopped"},
+	"resumed": {renamed: true, other: "running"},
+	"stopped": {renamed: false, other: "paused"},
+	"running": {renamed: false, other: "resumed"},
+}
+
 func (f *Filter) toMap() map[string]string {
 	var data = map[string]string{
 		"filter": f.StatusFilter,
@@ -228,7 +259,29 @@ func (f *Filter) toMap() map[string]string {
 // @return: :class:`TorrentInfoList` - `<https://github.com/qbittorrent/qBittorrent/wiki/WebUI-API-(qBittorrent-4.1)#get-torrent-list>`_
 // noqa: E501
 func (t *torrentsApi) Torrents(params *Filter) ([]*Torrent, error) {
-	_, body, err := t.postForTorrent("info", params.toMap())
+	return t.TorrentsContext(context.Background(), params)
+}
+
+// TorrentsContext is like Torrents but carries ctx for cancellation.
+func (t *torrentsApi) TorrentsContext(ctx context.Context, params *Filter) ([]*Torrent, error) {
+	if params.Tag != "" {
+		if err := t.client.requireFeatures(ctx, featureTagFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	data := params.toMap()
+	if filter, ok := statusFilterRenames[data["filter"]]; ok {
+		renamed, err := t.client.supports(ctx, featureStopStart)
+		if err != nil {
+			return nil, err
+		}
+		if renamed == filter.renamed {
+			data["filter"] = filter.other
+		}
+	}
+
+	_, body, err := t.postForTorrent(ctx, actionInfo, data)
 	if err != nil {
 		//logrus.Error(err)
 		return nil, err
@@ -250,7 +303,12 @@ type Category struct {
 
 // GetAllCategories Retrieve all category definitions.
 func (t *torrentsApi) GetAllCategories() ([]*Category, error) {
-	_, body, err := t.getForTorrent(actionGetAllCategories, nil)
+	return t.GetAllCategoriesContext(context.Background())
+}
+
+// GetAllCategoriesContext is like GetAllCategories but carries ctx for cancellation.
+func (t *torrentsApi) GetAllCategoriesContext(ctx context.Context) ([]*Category, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetAllCategories, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +328,12 @@ func (t *torrentsApi) GetAllCategories() ([]*Category, error) {
 
 // GetAllTags Retrieve all category definitions.
 func (t *torrentsApi) GetAllTags() ([]string, error) {
-	_, body, err := t.getForTorrent(actionGetAllTags, nil)
+	return t.GetAllTagsContext(context.Background())
+}
+
+// GetAllTagsContext is like GetAllTags but carries ctx for cancellation.
+func (t *torrentsApi) GetAllTagsContext(ctx context.Context) ([]string, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetAllTags, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -302,51 +365,72 @@ func (t *torrentsApi) GetAllTags() ([]string, error) {
 // :param seeding_time_limit: number of minutes to seed torrent (added in Web API 2.8.1)
 // :param download_path: location to download torrent content before moving to save_path (added in Web API 2.8.4)
 // :param use_download_path: whether the download_path should be used...defaults to True if download_path is specified (added in Web API 2.8.4)
+// :param stop_condition: MetadataReceived or FilesChecked to stop the torrent when reached (added in Web API 2.8.15)
+// :param inactive_seeding_time_limit: number of minutes to seed torrent while inactive (added in Web API 2.9.2)
+// :param add_to_top_of_queue: True to add torrent(s) to the top of the download queue (added in Web API 2.8.19)
+//
+// Zero values are not sent, so qBittorrent applies its own defaults for them.
 type DownloadBaseConfig struct {
-	SavePath           string        `json:"savepath"`           // 保存文件到：
-	Category           string        `json:"category"`           // 分类：
-	Tags               []string      `json:"tags"`               // 标签
-	SkipChecking       bool          `json:"skip_checking"`      // 跳过哈希校验
-	Paused             bool          `json:"paused"`             // 开始 Torrent
-	ContentLayout      ContentLayout `json:"contentLayout"`      // 内容布局：
-	Rename             string        `json:"rename"`             // 重命名 torrent
-	UpLimit            int           `json:"upLimit"`            // 限制上传速率 bytes/second
-	DlLimit            int           `json:"dlLimit"`            // 限制下载速率 bytes/second
-	AutoTMM            bool          `json:"autoTMM"`            // 自动 Torrent 管理
-	SequentialDownload bool          `json:"sequentialDownload"` // 按顺序下载
-	FirstLastPiecePrio bool          `json:"firstLastPiecePrio"` // 先下载首尾文件块
-	//RootFolder         bool        `json:"root_folder"`
-	//RatioLimit         float64        `json:"ratioLimit"`
-	//SeedingTimeLimit   string        `json:"seedingTimeLimit"`
-	//DownloadPath       string        `json:"downloadPath"`
-	//UseDownloadPath    bool          `json:"useDownloadPath"`
+	SavePath                 string           `json:"savepath"`                 // 保存文件到：
+	DownloadPath             string           `json:"downloadPath"`             // 未完成的 torrent 保存到：
+	UseDownloadPath          TriState         `json:"useDownloadPath"`          // 使用未完成的 torrent 保存路径
+	Cookie                   string           `json:"cookie"`                   // 下载链接使用的 Cookie
+	Category                 string           `json:"category"`                 // 分类：
+	Tags                     []string         `json:"tags"`                     // 标签
+	SkipChecking             TriState         `json:"skip_checking"`            // 跳过哈希校验
+	Paused                   TriState         `json:"paused"`                   // 开始 Torrent
+	StopCondition            StopCondition    `json:"stopCondition"`            // 停止条件：
+	ContentLayout            ContentLayout    `json:"contentLayout"`            // 内容布局：
+	RootFolder               TriState         `json:"root_folder"`              // 创建子文件夹
+	Rename                   string           `json:"rename"`                   // 重命名 torrent
+	UpLimit                  int              `json:"upLimit"`                  // 限制上传速率 bytes/second
+	DlLimit                  int              `json:"dlLimit"`                  // 限制下载速率 bytes/second
+	RatioLimit               RatioLimit       `json:"ratioLimit"`               // 分享率限制
+	SeedingTimeLimit         SeedingTimeLimit `json:"seedingTimeLimit"`         // 做种时间限制 minutes
+	InactiveSeedingTimeLimit SeedingTimeLimit `json:"inactiveSeedingTimeLimit"` // 非活跃做种时间限制 minutes
+	AutoTMM                  TriState         `json:"autoTMM"`                  // 自动 Torrent 管理
+	SequentialDownload       TriState         `json:"sequentialDownload"`       // 按顺序下载
+	FirstLastPiecePrio       TriState         `json:"firstLastPiecePrio"`       // 先下载首尾文件块
+	AddToTopOfQueue          TriState         `json:"addToTopOfQueue"`          // 添加到队列顶部
 }
 
 func (cfg DownloadBaseConfig) toMap() map[string]string {
-	data := map[string]string{
-		"autoTMM": "false",
-		//"savepath": cfg.SavePath,
-		"rename":   cfg.Rename,
-		"category": cfg.Category,
-		"tags":     strings.Join(cfg.Tags, ","),
-		"paused":   "false",
-		//"sequentialDownload": "false",
-		//"firstLastPiecePrio": "false",
-		"contentLayout": cfg.ContentLayout,
-		//"dlLimit":            "NaN",
-		//"upLimit":            "NaN",
-	}
-	if cfg.AutoTMM {
-		data["autoTMM"] = "true"
-	} else {
-		data["savepath"] = cfg.SavePath
-	}
-	if cfg.Paused {
-		data["paused"] = "true"
-	}
-	if cfg.ContentLayout == "" {
-		data["contentLayout"] = ContentLayoutOriginal
+	data := map[string]string{}
+	setString := func(key, val string) {
+		if val != "" {
+			data[key] = val
+		}
 	}
+	setInt := func(key string, val int) {
+		if val != 0 {
+			data[key] = strconv.Itoa(val)
+		}
+	}
+
+	// save path is ignored by qbittorrent with automatic torrent management
+	if cfg.AutoTMM != TriStateTrue {
+		setString("savepath", cfg.SavePath)
+	}
+	setString("downloadPath", cfg.DownloadPath)
+	setString("cookie", cfg.Cookie)
+	setString("category", cfg.Category)
+	setString("tags", strings.Join(cfg.Tags, ","))
+	setString("stopCondition", cfg.StopCondition)
+	setString("contentLayout", cfg.ContentLayout)
+	setString("rename", cfg.Rename)
+
+	useDownloadPath := cfg.UseDownloadPath
+	if useDownloadPath == TriStateDefault && cfg.DownloadPath != "" {
+		useDownloadPath = TriStateTrue
+	}
+	useDownloadPath.setParam(data, "useDownloadPath")
+	cfg.SkipChecking.setParam(data, "skip_checking")
+	cfg.Paused.setParam(data, "paused")
+	cfg.RootFolder.setParam(data, "root_folder")
+	cfg.AutoTMM.setParam(data, "autoTMM")
+	cfg.SequentialDownload.setParam(data, "sequentialDownload")
+	cfg.FirstLastPiecePrio.setParam(data, "firstLastPiecePrio")
+	cfg.AddToTopOfQueue.setParam(data, "addToTopOfQueue")
 
 	if cfg.DlLimit > 0 {
 		data["dlLimit"] = strconv.Itoa(cfg.DlLimit)
@@ -354,18 +438,81 @@ func (cfg DownloadBaseConfig) toMap() map[string]string {
 	if cfg.UpLimit > 0 {
 		data["upLimit"] = strconv.Itoa(cfg.UpLimit)
 	}
-	if cfg.SkipChecking {
-		data["skip_checking"] = "true"
+	// share limits use -2 for the global limit and -1 for no limit
+	if cfg.RatioLimit != 0 {
+		data["ratioLimit"] = cfg.RatioLimit.String()
 	}
-	if cfg.SequentialDownload {
-		data["sequentialDownload"] = "true"
+	setInt("seedingTimeLimit", int(cfg.SeedingTimeLimit))
+	setInt("inactiveSeedingTimeLimit", int(cfg.InactiveSeedingTimeLimit))
+	return data
+}
+
+// features lists the Web API features the config depends on.
+func (cfg DownloadBaseConfig) features() []feature {
+	var features []feature
+	if cfg.ContentLayout != "" {
+		features = append(features, featureContentLayout)
 	}
-	if cfg.FirstLastPiecePrio {
-		data["firstLastPiecePrio"] = "true"
+	if cfg.DownloadPath != "" || cfg.UseDownloadPath != TriStateDefault {
+		features = append(features, featureDownloadPath)
 	}
-	return data
+	return features
 }
 
+// addParams checks that the server supports cfg and adapts the parameters
+// of torrents/add to its version.
+func (t *torrentsApi) addParams(ctx context.Context, cfg DownloadBaseConfig, data map[string]string) (map[string]string, error) {
+	if err := t.client.requireFeatures(ctx, cfg.features()...); err != nil {
+		return nil, err
+	}
+
+	if paused, ok := data["paused"]; ok {
+		stopped, err := t.client.supports(ctx, featureStopStart)
+		if err != nil {
+			return nil, err
+		}
+		if stopped {
+			delete(data, "paused")
+			data["stopped"] = paused
+		}
+	}
+	return data, nil
+}
+
+// TriState is a boolean option which can also be left to the qBittorrent default.
+type TriState int8
+
+const (
+	TriStateDefault TriState = iota // not sent, qBittorrent decides
+	TriStateTrue
+	TriStateFalse
+)
+
+// TriStateOf returns TriStateTrue or TriStateFalse for b.
+func TriStateOf(b bool) TriState {
+	if b {
+		return TriStateTrue
+	}
+	return TriStateFalse
+}
+
+func (s TriState) setParam(data map[string]string, key string) {
+	switch s {
+	case TriStateTrue:
+		data[key] = "true"
+	case TriStateFalse:
+		data[key] = "false"
+	}
+}
+
+type StopCondition = string
+
+const (
+	StopConditionNone             = "None"             // 无
+	StopConditionMetadataReceived = "MetadataReceived" // 已收到元数据
+	StopConditionFilesChecked     = "FilesChecked"     // 文件已检查
+)
+
 type ContentLayout = string
 
 const (
@@ -396,107 +543,257 @@ func (cfg MagnetDLConfig) toMap() map[string]string {
 //
 // :return: “Ok.“ for success and “Fails.“ for failure
 func (t *torrentsApi) DownloadFromLink(cfg MagnetDLConfig) error {
-	resp, err := t.client.postMultipartData(apiNameTorrents, "add", cfg.toMap())
+	return t.DownloadFromLinkContext(context.Background(), cfg)
+}
+
+// DownloadFromLinkContext is like DownloadFromLink but carries ctx for cancellation.
+func (t *torrentsApi) DownloadFromLinkContext(ctx context.Context, cfg MagnetDLConfig) error {
+	data, err := t.addParams(ctx, cfg.DownloadBaseConfig, cfg.toMap())
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := t.client.postMultipartData(ctx, apiNameTorrents, actionAdd, data)
 	if err != nil {
 		return err
 	}
-	//fmt.Println(string(body))
-	//fmt.Println(resp.StatusCode)
-	if resp.StatusCode == http.StatusOK {
-		if string(body) == "Ok." {
-			return nil
-		} else {
-			return errors.New("DownloadFromLink Fails.")
-		}
-	}
 
-	return handleResponsesErr(resp.StatusCode)
+	body, err := readBody(apiNameTorrents, actionAdd, resp)
+	if err != nil {
+		return err
+	}
+	if string(body) != "Ok." {
+		return errors.New("DownloadFromLink Fails.")
+	}
+	return nil
 }
 
 // TorrentDLConfig
 // :param torrent_files: several options are available to send torrent files to qBittorrent:
-//   - single instance of bytes: useful if torrent file already read from disk or downloaded from internet.
-//   - single instance of file handle to torrent file: use open(<filepath>, 'rb') to open the torrent file.
-//   - single instance of a filepath to torrent file: e.g. '/home/user/torrent_filename.torrent'
-//   - an iterable of the single instances above to send more than one torrent file
-//   - dictionary with key/value pairs of torrent name and single instance of above object
-//     Note: The torrent name in a dictionary is useful to identify which torrent file
+//   - File: a filepath to torrent file: e.g. '/home/user/torrent_filename.torrent'
+//   - Torrents: any number of TorrentUpload, created by TorrentFromFile,
+//     TorrentFromReader or TorrentFromBytes, sent in one request. A reader
+//     which is not an io.Seeker is read fully into memory first
+//     Note: The torrent name is useful to identify which torrent file
 //     errored. qBittorrent provides back that name in the error text. If a torrent
 //     name is not provided, then the name of the file will be used. And in the case of
 //     bytes (or if filename cannot be determined), the value 'torrent__n' will be used
 type TorrentDLConfig struct {
-	File string `json:"file"`
+	File     string          `json:"file"`
+	Torrents []TorrentUpload `json:"-"`
 	DownloadBaseConfig
 }
 
+func (cfg TorrentDLConfig) uploads() []TorrentUpload {
+	var uploads []TorrentUpload
+	if cfg.File != "" {
+		uploads = append(uploads, TorrentFromFile(cfg.File))
+	}
+	return append(uploads, cfg.Torrents...)
+}
+
+// TorrentUpload is a single .torrent file sent to qBittorrent.
+// Exactly one of Path, Reader and Data is used, in that order.
+type TorrentUpload struct {
+	Name   string    // name reported back by qBittorrent in error texts
+	Path   string    // read the torrent from this file
+	Reader io.Reader // read the torrent from this reader, see TorrentFromReader
+	Data   []byte    // raw torrent contents
+}
+
+// TorrentFromFile uploads the torrent file at path.
+func TorrentFromFile(path string) TorrentUpload {
+	return TorrentUpload{Name: filepath.Base(path), Path: path}
+}
+
+// TorrentFromReader uploads the torrent read from r. To replay the upload
+// after the session expired, an io.Seeker is rewound to its current offset.
+// Any other reader is read fully into memory before the request, only
+// seekers and files are streamed.
+func TorrentFromReader(name string, r io.Reader) TorrentUpload {
+	return TorrentUpload{Name: name, Reader: r}
+}
+
+// TorrentFromBytes uploads the torrent contained in data.
+func TorrentFromBytes(name string, data []byte) TorrentUpload {
+	return TorrentUpload{Name: name, Data: data}
+}
+
+func (u TorrentUpload) fileName(index int) string {
+	switch {
+	case u.Name != "":
+		return u.Name
+	case u.Path != "":
+		return filepath.Base(u.Path)
+	}
+	return "torrent__" + strconv.Itoa(index+1)
+}
+
+func (u TorrentUpload) open() (io.ReadCloser, error) {
+	switch {
+	case u.Path != "":
+		file, err := os.Open(u.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "error opening file")
+		}
+		return file, nil
+	case u.Reader != nil:
+		if r, ok := u.Reader.(*rewindReader); ok {
+			if _, err := r.Seek(r.offset, io.SeekStart); err != nil {
+				return nil, errors.Wrap(err, "error rewinding reader")
+			}
+		}
+		return io.NopCloser(u.Reader), nil
+	}
+	return io.NopCloser(bytes.NewReader(u.Data)), nil
+}
+
+// size reports the number of bytes open will return, if it is known upfront.
+func (u TorrentUpload) size() (int64, bool) {
+	switch {
+	case u.Path != "":
+		info, err := os.Stat(u.Path)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size(), true
+	case u.Reader != nil:
+		if r, ok := u.Reader.(*rewindReader); ok {
+			return r.size, true
+		}
+		return 0, false
+	}
+	return int64(len(u.Data)), true
+}
+
+// rewindReader is a seekable upload source which open rewinds to offset.
+type rewindReader struct {
+	io.ReadSeeker
+	offset int64
+	size   int64
+}
+
+// replayableUploads makes every upload readable more than once, so that the
+// request can be replayed after a re-login. Seekable readers are rewound,
+// other readers are read into memory, torrent files are small.
+func replayableUploads(files []TorrentUpload) ([]TorrentUpload, error) {
+	res := make([]TorrentUpload, len(files))
+	for i, file := range files {
+		res[i] = file
+		if file.Path != "" || file.Reader == nil {
+			continue
+		}
+		if seeker, ok := file.Reader.(io.ReadSeeker); ok {
+			if r, err := newRewindReader(seeker); err == nil {
+				res[i].Reader = r
+				continue
+			}
+		}
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading torrent")
+		}
+		res[i].Reader, res[i].Data = nil, data
+	}
+	return res, nil
+}
+
+func newRewindReader(seeker io.ReadSeeker) (*rewindReader, error) {
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &rewindReader{ReadSeeker: seeker, offset: offset, size: end - offset}, nil
+}
+
+func (u TorrentUpload) validate() error {
+	if u.Path != "" {
+		if _, err := os.Stat(u.Path); err != nil {
+			return errors.Wrap(err, "error opening file")
+		}
+	}
+	return nil
+}
+
+// DownloadFromFile
+// Add one or more torrents by torrent files.
+//
+// :return: “Ok.“ for success and “Fails.“ for failure
 func (t *torrentsApi) DownloadFromFile(cfg TorrentDLConfig) error {
-	resp, err := t.client.postMultipartFile(apiNameTorrents, "add", cfg.File, cfg.toMap())
+	return t.DownloadFromFileContext(context.Background(), cfg)
+}
+
+// DownloadFromFileContext is like DownloadFromFile but carries ctx for cancellation.
+func (t *torrentsApi) DownloadFromFileContext(ctx context.Context, cfg TorrentDLConfig) error {
+	uploads := cfg.uploads()
+	if len(uploads) == 0 {
+		return errors.New("no torrent file to upload")
+	}
+	for _, upload := range uploads {
+		if err := upload.validate(); err != nil {
+			return err
+		}
+	}
+	data, err := t.addParams(ctx, cfg.DownloadBaseConfig, cfg.toMap())
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	resp, err := t.client.postMultipartFile(ctx, apiNameTorrents, actionAdd, uploads, data)
 	if err != nil {
 		return err
 	}
-	//fmt.Println(string(body))
-	//fmt.Println(resp.StatusCode)
-	if resp.StatusCode == http.StatusOK {
-		if string(body) == "Ok." {
-			return nil
-		} else {
-			return errors.New("DownloadFromLink Fails.")
-		}
-	}
 
-	return handleResponsesErr(resp.StatusCode)
+	body, err := readBody(apiNameTorrents, actionAdd, resp)
+	if err != nil {
+		return err
+	}
+	if string(body) != "Ok." {
+		return errors.New("DownloadFromFile Fails.")
+	}
+	return nil
 }
 
-func (t *torrentsApi) getForTorrent(path string, data map[string]string) (int, []byte, error) {
-	resp, err := t.client.request.get(apiNameTorrents, path, data)
+func (t *torrentsApi) getForTorrent(ctx context.Context, path string, data map[string]string) (int, []byte, error) {
+	resp, err := t.client.request.get(ctx, apiNameTorrents, path, data)
 	if err != nil {
 		return 0, nil, err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(apiNameTorrents, path, resp)
 	if err != nil {
 		return 0, nil, err
 	}
-	//fmt.Println(string(body))
-	//fmt.Println(resp.StatusCode)
-	if err := handleResponsesErr(resp.StatusCode); err != nil {
-		return 0, nil, err
-	}
 	return resp.StatusCode, body, nil
 }
 
-func (t *torrentsApi) postForTorrent(path string, data map[string]string) (int, []byte, error) {
-	resp, err := t.client.request.post(apiNameTorrents, path, data)
+func (t *torrentsApi) postForTorrent(ctx context.Context, path string, data map[string]string) (int, []byte, error) {
+	resp, err := t.client.request.post(ctx, apiNameTorrents, path, data)
 	if err != nil {
 		return 0, nil, err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(apiNameTorrents, path, resp)
 	if err != nil {
 		return 0, nil, err
 	}
-	//fmt.Println(string(body))
-	//fmt.Println(resp.StatusCode)
 	return resp.StatusCode, body, nil
 }
 
 type torrentsAction = string
 
 const (
+	actionInfo             torrentsAction = "info"
+	actionAdd              torrentsAction = "add"
 	actionPause            torrentsAction = "pause"
 	actionResume           torrentsAction = "resume"
+	actionStop             torrentsAction = "stop"
+	actionStart            torrentsAction = "start"
 	actionDelete           torrentsAction = "delete"
 	actionRecheck          torrentsAction = "recheck"
 	actionReAnnounce       torrentsAction = "reannounce"
@@ -562,58 +859,121 @@ func (a *action) setIntParam(key string, val int) *action {
 	return a
 }
 
-func (t *torrentsApi) actionByHashes(hashes []string, act *action) error {
-	return t.actionByParams(act.setParam("hashes", strings.Join(hashes, "|")))
+func (t *torrentsApi) actionByHashes(ctx context.Context, hashes []string, act *action) error {
+	return t.actionByParams(ctx, act.setParam("hashes", strings.Join(hashes, "|")))
+}
+
+func (t *torrentsApi) actionByParams(ctx context.Context, act *action) error {
+	_, _, err := t.client.postForTorrent(ctx, act.method, act.param)
+	return err
 }
 
-func (t *torrentsApi) actionByParams(act *action) error {
-	statusCode, _, err := t.client.postForTorrent(act.method, act.param)
+// pauseAction picks torrents/stop on qBittorrent 5 and torrents/pause before.
+func (t *torrentsApi) pauseAction(ctx context.Context) (torrentsAction, error) {
+	return t.actionSince(ctx, featureStopStart, actionStop, actionPause)
+}
+
+// resumeAction picks torrents/start on qBittorrent 5 and torrents/resume before.
+func (t *torrentsApi) resumeAction(ctx context.Context) (torrentsAction, error) {
+	return t.actionSince(ctx, featureStopStart, actionStart, actionResume)
+}
+
+// actionSince returns current if the server has the feature and legacy otherwise.
+func (t *torrentsApi) actionSince(ctx context.Context, f feature, current, legacy torrentsAction) (torrentsAction, error) {
+	ok, err := t.client.supports(ctx, f)
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	if statusCode == http.StatusOK {
-		return nil
+	if ok {
+		return current, nil
 	}
-	return handleResponsesErr(statusCode)
+	return legacy, nil
 }
 
 // Pause pause one or more torrents in qBittorrent.
+// On qBittorrent 5 and later the torrents are stopped.
 func (t *torrentsApi) Pause(ts ...*Torrent) error {
+	return t.PauseContext(context.Background(), ts...)
+}
+
+// PauseContext is like Pause but carries ctx for cancellation.
+func (t *torrentsApi) PauseContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionPause).withHashes(ts))
+	method, err := t.pauseAction(ctx)
+	if err != nil {
+		return err
+	}
+	return t.actionByParams(ctx, newAction(method).withHashes(ts))
 }
 
 // PauseByHashes pause torrents in qBittorrent by hashes.
 func (t *torrentsApi) PauseByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionPause))
+	return t.PauseByHashesContext(context.Background(), hashes)
+}
+
+// PauseByHashesContext is like PauseByHashes but carries ctx for cancellation.
+func (t *torrentsApi) PauseByHashesContext(ctx context.Context, hashes []string) error {
+	method, err := t.pauseAction(ctx)
+	if err != nil {
+		return err
+	}
+	return t.actionByHashes(ctx, hashes, newAction(method))
 }
 
 // PauseAll pause all torrents in qBittorrent.
 func (t *torrentsApi) PauseAll() error {
-	return t.PauseByHashes(actionForAll)
+	return t.PauseAllContext(context.Background())
+}
+
+// PauseAllContext is like PauseAll but carries ctx for cancellation.
+func (t *torrentsApi) PauseAllContext(ctx context.Context) error {
+	return t.PauseByHashesContext(ctx, actionForAll)
 }
 
 // Resume resume one or more torrents in qBittorrent.
+// On qBittorrent 5 and later the torrents are started.
 func (t *torrentsApi) Resume(ts ...*Torrent) error {
+	return t.ResumeContext(context.Background(), ts...)
+}
+
+// ResumeContext is like Resume but carries ctx for cancellation.
+func (t *torrentsApi) ResumeContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionResume).withHashes(ts))
+	method, err := t.resumeAction(ctx)
+	if err != nil {
+		return err
+	}
+	return t.actionByParams(ctx, newAction(method).withHashes(ts))
 }
 
 // ResumeByHashes resume torrents in qBittorrent by hashes.
 func (t *torrentsApi) ResumeByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionResume))
+	return t.ResumeByHashesContext(context.Background(), hashes)
+}
+
+// ResumeByHashesContext is like ResumeByHashes but carries ctx for cancellation.
+func (t *torrentsApi) ResumeByHashesContext(ctx context.Context, hashes []string) error {
+	method, err := t.resumeAction(ctx)
+	if err != nil {
+		return err
+	}
+	return t.actionByHashes(ctx, hashes, newAction(method))
 }
 
 // ResumeAll resume all torrents in qBittorrent.
 func (t *torrentsApi) ResumeAll() error {
-	return t.ResumeByHashes(actionForAll)
+	return t.ResumeAllContext(context.Background())
+}
+
+// ResumeAllContext is like ResumeAll but carries ctx for cancellation.
+func (t *torrentsApi) ResumeAllContext(ctx context.Context) error {
+	return t.ResumeByHashesContext(ctx, actionForAll)
 }
 
 // Delete
@@ -622,68 +982,123 @@ func (t *torrentsApi) ResumeAll() error {
 // param torrent_hashes: single torrent hash or list of torrent hashes. Or “all“ for all torrents.
 // return: None
 func (t *torrentsApi) Delete(deleteFiles bool, ts ...*Torrent) error {
+	return t.DeleteContext(context.Background(), deleteFiles, ts...)
+}
+
+// DeleteContext is like Delete but carries ctx for cancellation.
+func (t *torrentsApi) DeleteContext(ctx context.Context, deleteFiles bool, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionDelete).withHashes(ts).setBoolParam("deleteFiles", deleteFiles))
+	return t.actionByParams(ctx, newAction(actionDelete).withHashes(ts).setBoolParam("deleteFiles", deleteFiles))
 }
 
 func (t *torrentsApi) DeleteByHashes(deleteFiles bool, hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionDelete).setBoolParam("deleteFiles", deleteFiles))
+	return t.DeleteByHashesContext(context.Background(), deleteFiles, hashes)
+}
+
+// DeleteByHashesContext is like DeleteByHashes but carries ctx for cancellation.
+func (t *torrentsApi) DeleteByHashesContext(ctx context.Context, deleteFiles bool, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionDelete).setBoolParam("deleteFiles", deleteFiles))
 }
 
 func (t *torrentsApi) DeleteAll(deleteFiles bool) error {
-	return t.DeleteByHashes(deleteFiles, actionForAll)
+	return t.DeleteAllContext(context.Background(), deleteFiles)
+}
+
+// DeleteAllContext is like DeleteAll but carries ctx for cancellation.
+func (t *torrentsApi) DeleteAllContext(ctx context.Context, deleteFiles bool) error {
+	return t.DeleteByHashesContext(ctx, deleteFiles, actionForAll)
 }
 
 // Recheck a torrent in qBittorrent.
 func (t *torrentsApi) Recheck(ts ...*Torrent) error {
+	return t.RecheckContext(context.Background(), ts...)
+}
+
+// RecheckContext is like Recheck but carries ctx for cancellation.
+func (t *torrentsApi) RecheckContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionRecheck).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionRecheck).withHashes(ts))
 }
 
 func (t *torrentsApi) RecheckByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionRecheck))
+	return t.RecheckByHashesContext(context.Background(), hashes)
+}
+
+// RecheckByHashesContext is like RecheckByHashes but carries ctx for cancellation.
+func (t *torrentsApi) RecheckByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionRecheck))
 }
 
 func (t *torrentsApi) RecheckAll() error {
-	return t.RecheckByHashes(actionForAll)
+	return t.RecheckAllContext(context.Background())
+}
+
+// RecheckAllContext is like RecheckAll but carries ctx for cancellation.
+func (t *torrentsApi) RecheckAllContext(ctx context.Context) error {
+	return t.RecheckByHashesContext(ctx, actionForAll)
 }
 
 // ReAnnounce a torrent in qBittorrent.
 func (t *torrentsApi) ReAnnounce(ts ...*Torrent) error {
+	return t.ReAnnounceContext(context.Background(), ts...)
+}
+
+// ReAnnounceContext is like ReAnnounce but carries ctx for cancellation.
+func (t *torrentsApi) ReAnnounceContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionReAnnounce).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionReAnnounce).withHashes(ts))
 }
 
 func (t *torrentsApi) ReAnnounceByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionReAnnounce))
+	return t.ReAnnounceByHashesContext(context.Background(), hashes)
+}
+
+// ReAnnounceByHashesContext is like ReAnnounceByHashes but carries ctx for cancellation.
+func (t *torrentsApi) ReAnnounceByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionReAnnounce))
 }
 
 func (t *torrentsApi) ReAnnounceAll() error {
-	return t.ReAnnounceByHashes(actionForAll)
+	return t.ReAnnounceAllContext(context.Background())
+}
+
+// ReAnnounceAllContext is like ReAnnounceAll but carries ctx for cancellation.
+func (t *torrentsApi) ReAnnounceAllContext(ctx context.Context) error {
+	return t.ReAnnounceByHashesContext(ctx, actionForAll)
 }
 
 // IncreasePriority
 // Increase the priority of a torrent. Torrent Queuing must be enabled.
 // 向上移动队列
 func (t *torrentsApi) IncreasePriority(ts ...*Torrent) error {
+	return t.IncreasePriorityContext(context.Background(), ts...)
+}
+
+// IncreasePriorityContext is like IncreasePriority but carries ctx for cancellation.
+func (t *torrentsApi) IncreasePriorityContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionIncreasePriority).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionIncreasePriority).withHashes(ts))
 }
 
 func (t *torrentsApi) IncreasePriorityByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionIncreasePriority))
+	return t.IncreasePriorityByHashesContext(context.Background(), hashes)
+}
+
+// IncreasePriorityByHashesContext is like IncreasePriorityByHashes but carries ctx for cancellation.
+func (t *torrentsApi) IncreasePriorityByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionIncreasePriority))
 }
 
 //func (t *torrentsApi) IncreasePriorityAll() error {
@@ -694,15 +1109,25 @@ func (t *torrentsApi) IncreasePriorityByHashes(hashes []string) error {
 // Decrease the priority of a torrent. Torrent Queuing must be enabled.
 // 向下移动队列
 func (t *torrentsApi) DecreasePriority(ts ...*Torrent) error {
+	return t.DecreasePriorityContext(context.Background(), ts...)
+}
+
+// DecreasePriorityContext is like DecreasePriority but carries ctx for cancellation.
+func (t *torrentsApi) DecreasePriorityContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionDecreasePriority).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionDecreasePriority).withHashes(ts))
 }
 
 func (t *torrentsApi) DecreasePriorityByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionDecreasePriority))
+	return t.DecreasePriorityByHashesContext(context.Background(), hashes)
+}
+
+// DecreasePriorityByHashesContext is like DecreasePriorityByHashes but carries ctx for cancellation.
+func (t *torrentsApi) DecreasePriorityByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionDecreasePriority))
 }
 
 //func (t *torrentsApi) DecreasePriorityAll() error {
@@ -713,15 +1138,25 @@ func (t *torrentsApi) DecreasePriorityByHashes(hashes []string) error {
 // Set torrent as highest priority. Torrent Queuing must be enabled.
 // 移动到队列顶部
 func (t *torrentsApi) TopPriority(ts ...*Torrent) error {
+	return t.TopPriorityContext(context.Background(), ts...)
+}
+
+// TopPriorityContext is like TopPriority but carries ctx for cancellation.
+func (t *torrentsApi) TopPriorityContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionTopPriority).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionTopPriority).withHashes(ts))
 }
 
 func (t *torrentsApi) TopPriorityByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionTopPriority))
+	return t.TopPriorityByHashesContext(context.Background(), hashes)
+}
+
+// TopPriorityByHashesContext is like TopPriorityByHashes but carries ctx for cancellation.
+func (t *torrentsApi) TopPriorityByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionTopPriority))
 }
 
 //func (t *torrentsApi) TopPriorityAll() error {
@@ -732,15 +1167,25 @@ func (t *torrentsApi) TopPriorityByHashes(hashes []string) error {
 // Set torrent as highest priority. Torrent Queuing must be enabled.
 // 移动到队列底部
 func (t *torrentsApi) BottomPriority(ts ...*Torrent) error {
+	return t.BottomPriorityContext(context.Background(), ts...)
+}
+
+// BottomPriorityContext is like BottomPriority but carries ctx for cancellation.
+func (t *torrentsApi) BottomPriorityContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionBottomPriority).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionBottomPriority).withHashes(ts))
 }
 
 func (t *torrentsApi) BottomPriorityByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionBottomPriority))
+	return t.BottomPriorityByHashesContext(context.Background(), hashes)
+}
+
+// BottomPriorityByHashesContext is like BottomPriorityByHashes but carries ctx for cancellation.
+func (t *torrentsApi) BottomPriorityByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionBottomPriority))
 }
 
 //func (t *torrentsApi) BottomPriorityAll() error {
@@ -749,19 +1194,34 @@ func (t *torrentsApi) BottomPriorityByHashes(hashes []string) error {
 
 // SetCategory  Set a category for one or more torrents.
 func (t *torrentsApi) SetCategory(category string, ts ...*Torrent) error {
+	return t.SetCategoryContext(context.Background(), category, ts...)
+}
+
+// SetCategoryContext is like SetCategory but carries ctx for cancellation.
+func (t *torrentsApi) SetCategoryContext(ctx context.Context, category string, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionSetCategory).withHashes(ts).setParam("category", category))
+	return t.actionByParams(ctx, newAction(actionSetCategory).withHashes(ts).setParam("category", category))
 }
 
 func (t *torrentsApi) SetCategoryByHashes(category string, hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionSetCategory).setParam("category", category))
+	return t.SetCategoryByHashesContext(context.Background(), category, hashes)
+}
+
+// SetCategoryByHashesContext is like SetCategoryByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetCategoryByHashesContext(ctx context.Context, category string, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionSetCategory).setParam("category", category))
 }
 
 func (t *torrentsApi) SetCategoryForAll(category string) error {
-	return t.SetCategoryByHashes(category, actionForAll)
+	return t.SetCategoryForAllContext(context.Background(), category)
+}
+
+// SetCategoryForAllContext is like SetCategoryForAll but carries ctx for cancellation.
+func (t *torrentsApi) SetCategoryForAllContext(ctx context.Context, category string) error {
+	return t.SetCategoryByHashesContext(ctx, category, actionForAll)
 }
 
 // CreateCategory Create a new torrent category.
@@ -770,43 +1230,80 @@ func (t *torrentsApi) SetCategoryForAll(category string) error {
 // param download_path: download location for torrents with this category
 // param enable_download_path: True or False to enable or disable download path
 func (t *torrentsApi) CreateCategory(category Category) error {
+	return t.CreateCategoryContext(context.Background(), category)
+}
+
+// CreateCategoryContext is like CreateCategory but carries ctx for cancellation.
+func (t *torrentsApi) CreateCategoryContext(ctx context.Context, category Category) error {
+	if category.DownloadPath != "" {
+		if err := t.client.requireFeatures(ctx, featureDownloadPath); err != nil {
+			return err
+		}
+	}
+
 	act := newAction(actionCreateCategory).
 		setParam("category", category.Name).
 		setParam("savePath", category.SavePath).
 		setParam("downloadPath", category.DownloadPath).
 		setBoolParam("downloadPathEnabled", category.DownloadPath != "")
-	return t.actionByParams(act)
+	return t.actionByParams(ctx, act)
 }
 
 // EditCategory Edit an existing category.
 func (t *torrentsApi) EditCategory(category Category) error {
+	return t.EditCategoryContext(context.Background(), category)
+}
+
+// EditCategoryContext is like EditCategory but carries ctx for cancellation.
+func (t *torrentsApi) EditCategoryContext(ctx context.Context, category Category) error {
+	if category.DownloadPath != "" {
+		if err := t.client.requireFeatures(ctx, featureDownloadPath); err != nil {
+			return err
+		}
+	}
+
 	act := newAction(actionEditCategory).
 		setParam("category", category.Name).
 		setParam("savePath", category.SavePath).
 		setParam("downloadPath", category.DownloadPath).
 		setBoolParam("downloadPathEnabled", category.DownloadPath != "")
-	return t.actionByParams(act)
+	return t.actionByParams(ctx, act)
 }
 
 // RemoveCategories Delete one or more categories.
 func (t *torrentsApi) RemoveCategories(categories []string) error {
-	return t.actionByParams(newAction(actionRemoveCategories).setParam("categories", strings.Join(categories, "\n")))
+	return t.RemoveCategoriesContext(context.Background(), categories)
+}
+
+// RemoveCategoriesContext is like RemoveCategories but carries ctx for cancellation.
+func (t *torrentsApi) RemoveCategoriesContext(ctx context.Context, categories []string) error {
+	return t.actionByParams(ctx, newAction(actionRemoveCategories).setParam("categories", strings.Join(categories, "\n")))
 }
 
 // CreateTags Create one or more tags.
 func (t *torrentsApi) CreateTags(tags ...string) error {
+	return t.CreateTagsContext(context.Background(), tags...)
+}
+
+// CreateTagsContext is like CreateTags but carries ctx for cancellation.
+func (t *torrentsApi) CreateTagsContext(ctx context.Context, tags ...string) error {
 	if len(tags) == 0 {
 		return nil
 	}
-	return t.actionByParams(newAction(actionCreateTags).withTags(tags))
+	return t.actionByParams(ctx, newAction(actionCreateTags).withTags(tags))
 }
 
 // DeleteTags Delete one or more tags.
 func (t *torrentsApi) DeleteTags(tags ...string) error {
+	return t.DeleteTagsContext(context.Background(), tags...)
+}
+
+// DeleteTagsContext is like DeleteTags but carries ctx for cancellation.
+func (t *torrentsApi) DeleteTagsContext(ctx context.Context, tags ...string) error {
 	if len(tags) == 0 {
 		return nil
 	}
-	return t.actionByParams(newAction(actionDeleteTags).withTags(tags))
+	return t.actionByParams(ctx, newAction(actionDeleteTags).withTags(tags))
 }
 
 // AddTags Add one or more tags to one or more torrents.
@@ -815,64 +1312,124 @@ func (t *torrentsApi) DeleteTags(tags ...string) error {
 // param tags: tag name or list of tags
 // param torrent_hashes: single torrent hash or list of torrent hashes. Or “all“ for all torrents.
 func (t *torrentsApi) AddTags(tags []string, ts ...*Torrent) error {
-	return t.actionByParams(newAction(actionAddTags).withHashes(ts).withTags(tags))
+	return t.AddTagsContext(context.Background(), tags, ts...)
+}
+
+// AddTagsContext is like AddTags but carries ctx for cancellation.
+func (t *torrentsApi) AddTagsContext(ctx context.Context, tags []string, ts ...*Torrent) error {
+	return t.actionByParams(ctx, newAction(actionAddTags).withHashes(ts).withTags(tags))
 }
 
 func (t *torrentsApi) AddTagsByHashes(tags, hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionAddTags).withTags(tags))
+	return t.AddTagsByHashesContext(context.Background(), tags, hashes)
+}
+
+// AddTagsByHashesContext is like AddTagsByHashes but carries ctx for cancellation.
+func (t *torrentsApi) AddTagsByHashesContext(ctx context.Context, tags, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionAddTags).withTags(tags))
 }
 
 func (t *torrentsApi) AddTagsForAll(tags []string) error {
-	return t.AddTagsByHashes(tags, actionForAll)
+	return t.AddTagsForAllContext(context.Background(), tags)
+}
+
+// AddTagsForAllContext is like AddTagsForAll but carries ctx for cancellation.
+func (t *torrentsApi) AddTagsForAllContext(ctx context.Context, tags []string) error {
+	return t.AddTagsByHashesContext(ctx, tags, actionForAll)
 }
 
 // RemoveTags Remove one or more tags to one or more torrents.
 func (t *torrentsApi) RemoveTags(tags []string) error {
-	return t.actionByParams(newAction(actionRemoveTags).withTags(tags))
+	return t.RemoveTagsContext(context.Background(), tags)
+}
+
+// RemoveTagsContext is like RemoveTags but carries ctx for cancellation.
+func (t *torrentsApi) RemoveTagsContext(ctx context.Context, tags []string) error {
+	return t.actionByParams(ctx, newAction(actionRemoveTags).withTags(tags))
 }
 
 func (t *torrentsApi) RemoveTagsByHashes(tags, hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionRemoveTags).withTags(tags))
+	return t.RemoveTagsByHashesContext(context.Background(), tags, hashes)
+}
+
+// RemoveTagsByHashesContext is like RemoveTagsByHashes but carries ctx for cancellation.
+func (t *torrentsApi) RemoveTagsByHashesContext(ctx context.Context, tags, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionRemoveTags).withTags(tags))
 }
 
 func (t *torrentsApi) RemoveTagsForAll(tags []string) error {
-	return t.RemoveTagsByHashes(tags, actionForAll)
+	return t.RemoveTagsForAllContext(context.Background(), tags)
+}
+
+// RemoveTagsForAllContext is like RemoveTagsForAll but carries ctx for cancellation.
+func (t *torrentsApi) RemoveTagsForAllContext(ctx context.Context, tags []string) error {
+	return t.RemoveTagsByHashesContext(ctx, tags, actionForAll)
 }
 
 // ToggleFirstLastPiecePriority
 // Increase the priority of a torrent. Torrent Queuing must be enabled.
 // 选中/取消 先下载首尾文件块
 func (t *torrentsApi) ToggleFirstLastPiecePriority(ts ...*Torrent) error {
+	return t.ToggleFirstLastPiecePriorityContext(context.Background(), ts...)
+}
+
+// ToggleFirstLastPiecePriorityContext is like ToggleFirstLastPiecePriority but carries ctx for cancellation.
+func (t *torrentsApi) ToggleFirstLastPiecePriorityContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionToggleFirstLastPiecePriority).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionToggleFirstLastPiecePriority).withHashes(ts))
 }
 
 func (t *torrentsApi) ToggleFirstLastPiecePriorityByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionToggleFirstLastPiecePriority))
+	return t.ToggleFirstLastPiecePriorityByHashesContext(context.Background(), hashes)
+}
+
+// ToggleFirstLastPiecePriorityByHashesContext is like ToggleFirstLastPiecePriorityByHashes but carries ctx for cancellation.
+func (t *torrentsApi) ToggleFirstLastPiecePriorityByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionToggleFirstLastPiecePriority))
 }
 
 func (t *torrentsApi) ToggleFirstLastPiecePriorityForAll() error {
-	return t.ToggleFirstLastPiecePriorityByHashes(actionForAll)
+	return t.ToggleFirstLastPiecePriorityForAllContext(context.Background())
+}
+
+// ToggleFirstLastPiecePriorityForAllContext is like ToggleFirstLastPiecePriorityForAll but carries ctx for cancellation.
+func (t *torrentsApi) ToggleFirstLastPiecePriorityForAllContext(ctx context.Context) error {
+	return t.ToggleFirstLastPiecePriorityByHashesContext(ctx, actionForAll)
 }
 
 // ToggleSequentialDownload
 // Increase the priority of a torrent. Torrent Queuing must be enabled.
 // 选中/取消 按顺序下载
 func (t *torrentsApi) ToggleSequentialDownload(ts ...*Torrent) error {
+	return t.ToggleSequentialDownloadContext(context.Background(), ts...)
+}
+
+// ToggleSequentialDownloadContext is like ToggleSequentialDownload but carries ctx for cancellation.
+func (t *torrentsApi) ToggleSequentialDownloadContext(ctx context.Context, ts ...*Torrent) error {
 	if len(ts) == 0 {
 		return nil
 	}
 
-	return t.actionByParams(newAction(actionToggleSequentialDownload).withHashes(ts))
+	return t.actionByParams(ctx, newAction(actionToggleSequentialDownload).withHashes(ts))
 }
 
 func (t *torrentsApi) ToggleSequentialDownloadByHashes(hashes []string) error {
-	return t.actionByHashes(hashes, newAction(actionToggleSequentialDownload))
+	return t.ToggleSequentialDownloadByHashesContext(context.Background(), hashes)
+}
+
+// ToggleSequentialDownloadByHashesContext is like ToggleSequentialDownloadByHashes but carries ctx for cancellation.
+func (t *torrentsApi) ToggleSequentialDownloadByHashesContext(ctx context.Context, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionToggleSequentialDownload))
 }
 
 func (t *torrentsApi) ToggleSequentialDownloadForAll() error {
-	return t.ToggleSequentialDownloadByHashes(actionForAll)
+	return t.ToggleSequentialDownloadForAllContext(context.Background())
+}
+
+// ToggleSequentialDownloadForAllContext is like ToggleSequentialDownloadForAll but carries ctx for cancellation.
+func (t *torrentsApi) ToggleSequentialDownloadForAllContext(ctx context.Context) error {
+	return t.ToggleSequentialDownloadByHashesContext(ctx, actionForAll)
 }
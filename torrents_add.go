@@ -0,0 +1,387 @@
+package qbittorrent_api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xiangyt/qbittorrent-api/magnet"
+)
+
+// ResolveConfig controls how AddFromLink and AddFromFile find out the hashes
+// of the added torrents.
+//
+// Hashes of magnet links and torrent files are computed locally. Torrents
+// added from http(s) URLs or plain readers are tagged with a temporary marker
+// tag, and the torrent list is polled until they show up. This needs the tag
+// filter of Web API 2.8.3.
+//
+// qBittorrent silently ignores torrents which are already present. Locally
+// hashed torrents are looked up before adding, tagged ones never show up with
+// the marker tag, and once no further torrent appeared for SettleTime they
+// are given up on. Either way the hashes of the added torrents are returned
+// together with ErrTorrentNotAdded.
+type ResolveConfig struct {
+	WaitForMetadata bool          // also wait until qBittorrent fetched the metadata of magnet links
+	PollInterval    time.Duration // interval to poll the torrent list, 500ms by default
+	SettleTime      time.Duration // give up on missing torrents once nothing changed this long, 10s by default
+	Timeout         time.Duration // give up after this long, 1 minute by default
+}
+
+const (
+	defaultResolvePollInterval = 500 * time.Millisecond
+	defaultResolveSettleTime   = 10 * time.Second
+	defaultResolveTimeout      = time.Minute
+	markerTagPrefix            = "go-qbittorrent-"
+)
+
+func (rc ResolveConfig) withDefaults() ResolveConfig {
+	if rc.PollInterval <= 0 {
+		rc.PollInterval = defaultResolvePollInterval
+	}
+	if rc.SettleTime <= 0 {
+		rc.SettleTime = defaultResolveSettleTime
+	}
+	if rc.Timeout <= 0 {
+		rc.Timeout = defaultResolveTimeout
+	}
+	return rc
+}
+
+// AddFromLink adds torrents like DownloadFromLink and returns their hashes,
+// in the order of cfg.Urls for magnet links followed by the others. Links of
+// torrents which are already present are left out and reported with
+// ErrTorrentNotAdded, see ResolveConfig.
+func (t *torrentsApi) AddFromLink(cfg MagnetDLConfig, rc ResolveConfig) ([]string, error) {
+	return t.AddFromLinkContext(context.Background(), cfg, rc)
+}
+
+// AddFromLinkContext is like AddFromLink but carries ctx for cancellation.
+func (t *torrentsApi) AddFromLinkContext(ctx context.Context, cfg MagnetDLConfig, rc ResolveConfig) ([]string, error) {
+	var known []string
+	unresolved := 0
+	for _, link := range cfg.Urls {
+		if hash := magnet.ParseHash(link); hash != "" {
+			known = append(known, hash)
+		} else {
+			unresolved++
+		}
+	}
+
+	return t.addAndResolve(ctx, rc, known, unresolved, &cfg.DownloadBaseConfig, func(ctx context.Context) error {
+		return t.DownloadFromLinkContext(ctx, cfg)
+	})
+}
+
+// AddFromFile adds torrents like DownloadFromFile and returns their hashes.
+func (t *torrentsApi) AddFromFile(cfg TorrentDLConfig, rc ResolveConfig) ([]string, error) {
+	return t.AddFromFileContext(context.Background(), cfg, rc)
+}
+
+// AddFromFileContext is like AddFromFile but carries ctx for cancellation.
+func (t *torrentsApi) AddFromFileContext(ctx context.Context, cfg TorrentDLConfig, rc ResolveConfig) ([]string, error) {
+	var known []string
+	unresolved := 0
+	for _, upload := range cfg.uploads() {
+		if hash, err := upload.infoHash(); err == nil {
+			known = append(known, hash)
+		} else {
+			unresolved++
+		}
+	}
+
+	return t.addAndResolve(ctx, rc, known, unresolved, &cfg.DownloadBaseConfig, func(ctx context.Context) error {
+		return t.DownloadFromFileContext(ctx, cfg)
+	})
+}
+
+// addAndResolve runs add and waits for the unresolved torrents to appear
+// with a marker tag, which is removed again afterwards.
+func (t *torrentsApi) addAndResolve(ctx context.Context, rc ResolveConfig, known []string, unresolved int,
+	base *DownloadBaseConfig, add func(ctx context.Context) error) ([]string, error) {
+	rc = rc.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, rc.Timeout)
+	defer cancel()
+
+	var marker string
+	if unresolved > 0 {
+		// fail before adding, rather than leave torrents with a marker tag
+		// which can't be looked up
+		if err := t.client.requireFeatures(ctx, featureTagFilter); err != nil {
+			return nil, err
+		}
+		marker = newMarkerTag()
+		base.Tags = append(append([]string{}, base.Tags...), marker)
+		defer t.deleteMarkerTag(marker)
+	}
+
+	known = dedupe(known)
+	present, err := t.presentHashes(ctx, known)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := add(ctx); err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	for _, hash := range known {
+		if !present[hash] {
+			hashes = append(hashes, hash)
+		}
+	}
+	if unresolved > 0 {
+		found, err := t.waitForTagged(ctx, marker, known, unresolved, rc.PollInterval, rc.SettleTime)
+		hashes = append(hashes, found...)
+		if err != nil {
+			return hashes, errors.Wrap(err, "failed to resolve added torrents")
+		}
+	}
+
+	if rc.WaitForMetadata {
+		if err := t.waitForMetadata(ctx, hashes, rc.PollInterval); err != nil {
+			return hashes, errors.Wrap(err, "failed to wait for metadata")
+		}
+	}
+	if len(present) > 0 {
+		return hashes, errors.Wrapf(ErrTorrentNotAdded, "%d of %d torrents are already present", len(present), len(known)+unresolved)
+	}
+	return hashes, nil
+}
+
+// presentHashes returns which of the hashes are already in the torrent list.
+func (t *torrentsApi) presentHashes(ctx context.Context, hashes []string) (map[string]bool, error) {
+	present := map[string]bool{}
+	if len(hashes) == 0 {
+		return present, nil
+	}
+	ts, err := t.TorrentsContext(ctx, &Filter{Hashes: hashes})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up torrents")
+	}
+	for _, torrent := range ts {
+		present[torrent.Hash] = true
+	}
+	return present, nil
+}
+
+// waitForTagged polls until count torrents other than known carry the tag.
+// If no further torrent showed up for settle, the missing torrents are
+// assumed to be already present and ErrTorrentNotAdded is returned.
+func (t *torrentsApi) waitForTagged(ctx context.Context, tag string, known []string, count int, interval, settle time.Duration) ([]string, error) {
+	skip := map[string]bool{}
+	for _, hash := range known {
+		skip[hash] = true
+	}
+
+	var found []string
+	lastChange := time.Now()
+	err := poll(ctx, interval, func() (bool, error) {
+		ts, err := t.TorrentsContext(ctx, &Filter{Tag: tag})
+		if err != nil {
+			return false, err
+		}
+		before := len(found)
+		found = found[:0]
+		for _, torrent := range ts {
+			if !skip[torrent.Hash] {
+				found = append(found, torrent.Hash)
+			}
+		}
+		if len(found) >= count {
+			return true, nil
+		}
+		if len(found) != before {
+			lastChange = time.Now()
+		} else if time.Since(lastChange) >= settle {
+			return false, errors.Wrapf(ErrTorrentNotAdded, "%d of %d torrents did not appear, they may already be present", count-len(found), count)
+		}
+		return false, nil
+	})
+	return found, err
+}
+
+// waitForMetadata polls until none of the torrents is still fetching metadata.
+func (t *torrentsApi) waitForMetadata(ctx context.Context, hashes []string, interval time.Duration) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return poll(ctx, interval, func() (bool, error) {
+		ts, err := t.TorrentsContext(ctx, &Filter{Hashes: hashes})
+		if err != nil {
+			return false, err
+		}
+		if len(ts) < len(hashes) {
+			return false, nil
+		}
+		for _, torrent := range ts {
+			if torrent.State == StateMetadataDownload || torrent.State == StateForcedMetadataDownload {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+func (t *torrentsApi) deleteMarkerTag(tag string) {
+	// the caller's context may be done already, the tag should go anyway
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := t.DeleteTagsContext(ctx, tag); err != nil {
+		logrus.Warnf("failed to delete marker tag %s: %v", tag, err)
+	}
+}
+
+func newMarkerTag() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return markerTagPrefix + strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return markerTagPrefix + hex.EncodeToString(b)
+}
+
+// poll calls fn every interval until it reports done, fails or ctx is done.
+func poll(ctx context.Context, interval time.Duration, fn func() (bool, error)) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+		timer.Reset(interval)
+	}
+}
+
+func dedupe(hashes []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, hash := range hashes {
+		if !seen[hash] {
+			seen[hash] = true
+			res = append(res, hash)
+		}
+	}
+	return res
+}
+
+// infoHash computes the hash qBittorrent uses for the torrent. Readers can
+// only be consumed once and are never hashed.
+func (u TorrentUpload) infoHash() (string, error) {
+	switch {
+	case u.Path != "":
+		data, err := os.ReadFile(u.Path)
+		if err != nil {
+			return "", err
+		}
+		return torrentInfoHash(data)
+	case u.Reader != nil:
+		return "", errors.New("can't hash a reader")
+	}
+	return torrentInfoHash(u.Data)
+}
+
+var errInvalidTorrent = errors.New("invalid torrent file")
+
+// torrentInfoHash returns the sha1 of the bencoded info dictionary, or the
+// truncated sha256 for v2-only torrents which have no "pieces" key.
+func torrentInfoHash(data []byte) (string, error) {
+	start, end, err := bencodeDictValue(data, 0, "info")
+	if err != nil {
+		return "", err
+	}
+	info := data[start:end]
+	if info[0] != 'd' {
+		return "", errInvalidTorrent
+	}
+
+	if _, _, err := bencodeDictValue(info, 0, "pieces"); err != nil {
+		sum := sha256.Sum256(info)
+		return hex.EncodeToString(sum[:20]), nil
+	}
+	sum := sha1.Sum(info)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// bencodeDictValue finds key in the dictionary starting at i and returns the
+// bounds of its raw value.
+func bencodeDictValue(data []byte, i int, key string) (int, int, error) {
+	if i >= len(data) || data[i] != 'd' {
+		return 0, 0, errInvalidTorrent
+	}
+	i++
+	for i < len(data) && data[i] != 'e' {
+		keyEnd, err := bencodeEnd(data, i)
+		if err != nil {
+			return 0, 0, err
+		}
+		valEnd, err := bencodeEnd(data, keyEnd)
+		if err != nil {
+			return 0, 0, err
+		}
+		colon := bytes.IndexByte(data[i:keyEnd], ':')
+		if colon >= 0 && string(data[i+colon+1:keyEnd]) == key {
+			return keyEnd, valEnd, nil
+		}
+		i = valEnd
+	}
+	return 0, 0, errors.Wrap(errInvalidTorrent, "missing "+key)
+}
+
+// bencodeEnd returns the offset right after the bencoded value starting at i.
+func bencodeEnd(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, errInvalidTorrent
+	}
+	switch c := data[i]; {
+	case c == 'i':
+		end := bytes.IndexByte(data[i:], 'e')
+		if end < 0 {
+			return 0, errInvalidTorrent
+		}
+		return i + end + 1, nil
+	case c == 'l' || c == 'd':
+		i++
+		for i < len(data) && data[i] != 'e' {
+			end, err := bencodeEnd(data, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		}
+		if i >= len(data) {
+			return 0, errInvalidTorrent
+		}
+		return i + 1, nil
+	case c >= '0' && c <= '9':
+		colon := bytes.IndexByte(data[i:], ':')
+		if colon < 0 {
+			return 0, errInvalidTorrent
+		}
+		n, err := strconv.Atoi(string(data[i : i+colon]))
+		if err != nil || n < 0 {
+			return 0, errInvalidTorrent
+		}
+		// compare before adding, a crafted length could overflow
+		if n > len(data)-i-colon-1 {
+			return 0, errInvalidTorrent
+		}
+		return i + colon + 1 + n, nil
+	}
+	return 0, errInvalidTorrent
+}
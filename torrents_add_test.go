@@ -0,0 +1,165 @@
+package qbittorrent_api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTorrentInfoHash(t *testing.T) {
+	info := "d6:lengthi12e4:name5:a.txt12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaae"
+	data := "d8:announce14:http://tracker7:comment2:hi4:info" + info + "e"
+	sum := sha1.Sum([]byte(info))
+
+	got, err := torrentInfoHash([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("torrentInfoHash() = %s, want %s", got, want)
+	}
+
+	for _, invalid := range []string{"", "d4:infoi1e", "l4:infoe", "d8:announce3:abe", "d4:info9223372036854775807:de"} {
+		if _, err := torrentInfoHash([]byte(invalid)); err == nil {
+			t.Errorf("torrentInfoHash(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestAddFromLink(t *testing.T) {
+	const magnetHash = "b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aa"
+	const urlHash = "e40127b663555092b5ac7b1f621cb2a7364adbe1"
+
+	srv := newFakeServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var marker string
+	var deleted []string
+	added := false
+	srv.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		mu.Lock()
+		defer mu.Unlock()
+		added = true
+		for _, tag := range strings.Split(r.FormValue("tags"), ",") {
+			if strings.HasPrefix(tag, markerTagPrefix) {
+				marker = tag
+			}
+		}
+		w.Write([]byte("Ok."))
+	})
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		ts := []*Torrent{}
+		if tag := r.FormValue("tag"); tag != "" && tag == marker {
+			ts = append(ts, &Torrent{Hash: magnetHash}, &Torrent{Hash: urlHash})
+		}
+		if r.FormValue("hashes") != "" && added {
+			ts = append(ts, &Torrent{Hash: magnetHash, State: StateStalledDownload}, &Torrent{Hash: urlHash, State: StateDownloading})
+		}
+		json.NewEncoder(w).Encode(ts)
+	})
+	srv.handle("torrents/deleteTags", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = append(deleted, r.FormValue("tags"))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	hashes, err := c.AddFromLink(MagnetDLConfig{
+		Urls:               []string{"magnet:?xt=urn:btih:" + magnetHash + "&dn=a", "http://example.com/b.torrent"},
+		DownloadBaseConfig: DownloadBaseConfig{Tags: []string{"tag1"}},
+	}, ResolveConfig{WaitForMetadata: true, PollInterval: 10 * time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{magnetHash, urlHash}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("hashes = %v, want %v", hashes, want)
+	}
+	if marker == "" || !reflect.DeepEqual(deleted, []string{marker}) {
+		t.Errorf("marker %q, deleted tags %v", marker, deleted)
+	}
+}
+
+func TestAddFromLinkNotResolvable(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	adds := 0
+	srv.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		adds++
+		w.Write([]byte("Ok."))
+	})
+	// the link is already present, no torrent ever carries the marker tag
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	srv.handle("torrents/deleteTags", func(w http.ResponseWriter, r *http.Request) {})
+
+	cfg := MagnetDLConfig{Urls: []string{"http://example.com/b.torrent"}}
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	start := time.Now()
+	_, err := c.AddFromLink(cfg, ResolveConfig{PollInterval: 5 * time.Millisecond, SettleTime: 50 * time.Millisecond, Timeout: 10 * time.Second})
+	if !errors.Is(err, ErrTorrentNotAdded) {
+		t.Errorf("got %v, want ErrTorrentNotAdded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %v", elapsed)
+	}
+
+	// without the tag filter the torrent must not be added at all
+	old := newFakeServerWithVersion("2.8.2")
+	defer old.Close()
+	old.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("torrent added on a server without tag filter")
+	})
+	c = NewClient(old.URL, WithAuth(name, pwd))
+	if _, err := c.AddFromLink(cfg, ResolveConfig{}); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("got %v, want ErrUnsupportedByServer", err)
+	}
+}
+
+func TestAddFromLinkAlreadyPresent(t *testing.T) {
+	const present = "b7092da3a99dd0fa5a701ebe2a6dcb897a6e50aa"
+	const added = "e40127b663555092b5ac7b1f621cb2a7364adbe1"
+
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Ok."))
+	})
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		ts := []*Torrent{}
+		for _, hash := range strings.Split(r.FormValue("hashes"), "|") {
+			if hash == present {
+				ts = append(ts, &Torrent{Hash: present, State: StateUploading})
+			}
+		}
+		json.NewEncoder(w).Encode(ts)
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	hashes, err := c.AddFromLink(MagnetDLConfig{
+		Urls: []string{"magnet:?xt=urn:btih:" + present, "magnet:?xt=urn:btih:" + added},
+	}, ResolveConfig{})
+	if !errors.Is(err, ErrTorrentNotAdded) {
+		t.Errorf("got %v, want ErrTorrentNotAdded", err)
+	}
+	if want := []string{added}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("hashes = %v, want %v", hashes, want)
+	}
+}
@@ -1,14 +1,21 @@
 package qbittorrent_api
 
 import (
+	"context"
 	"encoding/json"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 const (
 	actionGetFiles        torrentsAction = "files"
 	actionSetFilePriority torrentsAction = "filePrio"
+	actionRenameTorrent   torrentsAction = "rename"
+	actionRenameFile      torrentsAction = "renameFile"
+	actionRenameFolder    torrentsAction = "renameFolder"
 )
 
 type FilePriority = int
@@ -58,13 +65,23 @@ func (tfs TorrentFiles) Filter(fn func(tf *TorrentFile) bool) TorrentFiles {
 // GetAllTorrentFiles
 // Get torrent contents.
 func (t *torrentsApi) GetAllTorrentFiles(torrent *Torrent) ([]*TorrentFile, error) {
-	return t.GetAllTorrentFilesByHash(torrent.Hash)
+	return t.GetAllTorrentFilesContext(context.Background(), torrent)
+}
+
+// GetAllTorrentFilesContext is like GetAllTorrentFiles but carries ctx for cancellation.
+func (t *torrentsApi) GetAllTorrentFilesContext(ctx context.Context, torrent *Torrent) ([]*TorrentFile, error) {
+	return t.GetAllTorrentFilesByHashContext(ctx, torrent.Hash)
 }
 
 // GetAllTorrentFilesByHash
 // Get torrent contents.
 func (t *torrentsApi) GetAllTorrentFilesByHash(hash string) ([]*TorrentFile, error) {
-	_, body, err := t.getForTorrent(actionGetFiles, map[string]string{
+	return t.GetAllTorrentFilesByHashContext(context.Background(), hash)
+}
+
+// GetAllTorrentFilesByHashContext is like GetAllTorrentFilesByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetAllTorrentFilesByHashContext(ctx context.Context, hash string) ([]*TorrentFile, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetFiles, map[string]string{
 		"hash": hash,
 	})
 	if err != nil {
@@ -80,6 +97,11 @@ func (t *torrentsApi) GetAllTorrentFilesByHash(hash string) ([]*TorrentFile, err
 }
 
 func (t *torrentsApi) SetFilePriority(hash string, tfs []*TorrentFile, priority FilePriority) error {
+	return t.SetFilePriorityContext(context.Background(), hash, tfs, priority)
+}
+
+// SetFilePriorityContext is like SetFilePriority but carries ctx for cancellation.
+func (t *torrentsApi) SetFilePriorityContext(ctx context.Context, hash string, tfs []*TorrentFile, priority FilePriority) error {
 	if len(tfs) == 0 {
 		return nil
 	}
@@ -88,8 +110,258 @@ func (t *torrentsApi) SetFilePriority(hash string, tfs []*TorrentFile, priority
 		ids = append(ids, strconv.Itoa(tf.Index))
 	}
 
-	return t.actionByParams(newAction(actionSetFilePriority).
+	return t.actionByParams(ctx, newAction(actionSetFilePriority).
 		setParam("hash", hash).
 		setParam("id", strings.Join(ids, "|")).
 		setIntParam("priority", priority))
 }
+
+// RenameTorrent Rename a torrent.
+func (t *torrentsApi) RenameTorrent(torrent *Torrent, name string) error {
+	return t.RenameTorrentContext(context.Background(), torrent, name)
+}
+
+// RenameTorrentContext is like RenameTorrent but carries ctx for cancellation.
+func (t *torrentsApi) RenameTorrentContext(ctx context.Context, torrent *Torrent, name string) error {
+	return t.RenameTorrentByHashContext(ctx, torrent.Hash, name)
+}
+
+func (t *torrentsApi) RenameTorrentByHash(hash, name string) error {
+	return t.RenameTorrentByHashContext(context.Background(), hash, name)
+}
+
+// RenameTorrentByHashContext is like RenameTorrentByHash but carries ctx for cancellation.
+func (t *torrentsApi) RenameTorrentByHashContext(ctx context.Context, hash, name string) error {
+	if name == "" {
+		return errors.New("empty torrent name")
+	}
+
+	return t.actionByParams(ctx, newAction(actionRenameTorrent).
+		setParam("hash", hash).
+		setParam("name", name))
+}
+
+// RenameFile Rename or move a file inside a torrent, paths are TorrentFile.Name.
+// The server answers ErrConflict if newPath already exists.
+func (t *torrentsApi) RenameFile(torrent *Torrent, oldPath, newPath string) error {
+	return t.RenameFileContext(context.Background(), torrent, oldPath, newPath)
+}
+
+// RenameFileContext is like RenameFile but carries ctx for cancellation.
+func (t *torrentsApi) RenameFileContext(ctx context.Context, torrent *Torrent, oldPath, newPath string) error {
+	return t.RenameFileByHashContext(ctx, torrent.Hash, oldPath, newPath)
+}
+
+func (t *torrentsApi) RenameFileByHash(hash, oldPath, newPath string) error {
+	return t.RenameFileByHashContext(context.Background(), hash, oldPath, newPath)
+}
+
+// RenameFileByHashContext is like RenameFileByHash but carries ctx for cancellation.
+func (t *torrentsApi) RenameFileByHashContext(ctx context.Context, hash, oldPath, newPath string) error {
+	return t.renamePath(ctx, actionRenameFile, hash, oldPath, newPath)
+}
+
+// RenameFolder Rename or move a folder inside a torrent.
+func (t *torrentsApi) RenameFolder(torrent *Torrent, oldPath, newPath string) error {
+	return t.RenameFolderContext(context.Background(), torrent, oldPath, newPath)
+}
+
+// RenameFolderContext is like RenameFolder but carries ctx for cancellation.
+func (t *torrentsApi) RenameFolderContext(ctx context.Context, torrent *Torrent, oldPath, newPath string) error {
+	return t.RenameFolderByHashContext(ctx, torrent.Hash, oldPath, newPath)
+}
+
+func (t *torrentsApi) RenameFolderByHash(hash, oldPath, newPath string) error {
+	return t.RenameFolderByHashContext(context.Background(), hash, oldPath, newPath)
+}
+
+// RenameFolderByHashContext is like RenameFolderByHash but carries ctx for cancellation.
+func (t *torrentsApi) RenameFolderByHashContext(ctx context.Context, hash, oldPath, newPath string) error {
+	return t.renamePath(ctx, actionRenameFolder, hash, oldPath, newPath)
+}
+
+func (t *torrentsApi) renamePath(ctx context.Context, action torrentsAction, hash, oldPath, newPath string) error {
+	if oldPath == "" || newPath == "" {
+		return errors.New("empty path")
+	}
+	if err := t.client.requireFeatures(ctx, featureRenamePaths); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(action).
+		setParam("hash", hash).
+		setParam("oldPath", oldPath).
+		setParam("newPath", newPath))
+}
+
+// FileRename is a single step of a batch rename.
+type FileRename struct {
+	OldPath string
+	NewPath string
+}
+
+// renameTempSuffix marks the intermediate names PlanFileRenames uses to
+// break cycles such as swapping two file names.
+const renameTempSuffix = ".go-qbittorrent-rename"
+
+// PlanFileRenames maps the name of every file with fn and returns the
+// renames in an order where no step targets a path which is still in use,
+// either by a file or as a folder of other files. Files for which fn returns
+// the old or an empty name are kept. Cycles are broken by moving one file to
+// a temporary name first. Renames which would conflict in the final layout
+// are rejected before anything is planned.
+func PlanFileRenames(files []*TorrentFile, fn func(name string) string) ([]FileRename, error) {
+	occupied := map[string]bool{}
+	for _, file := range files {
+		occupied[file.Name] = true
+	}
+
+	pending := map[string]string{}
+	targets := map[string]string{}
+	for _, file := range files {
+		name := fn(file.Name)
+		if name == "" || name == file.Name {
+			continue
+		}
+		if other, ok := targets[name]; ok {
+			return nil, errors.Errorf("%q and %q would both be renamed to %q", other, file.Name, name)
+		}
+		if strings.HasPrefix(file.Name, name+"/") {
+			return nil, errors.Errorf("renaming %q to %q would replace its own folder", file.Name, name)
+		}
+		targets[name] = file.Name
+		pending[file.Name] = name
+	}
+
+	final := map[string]bool{}
+	for _, file := range files {
+		if name, ok := pending[file.Name]; ok {
+			final[name] = true
+		} else {
+			final[file.Name] = true
+		}
+	}
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		from := targets[name]
+		if _, moving := pending[name]; occupied[name] && !moving {
+			return nil, errors.Errorf("renaming %q to %q would overwrite an existing file", from, name)
+		}
+		delete(final, name)
+		other, conflict := pathConflict(final, name)
+		final[name] = true
+		if conflict {
+			return nil, errors.Errorf("renaming %q to %q conflicts with %q, a path can't be both a file and a folder", from, name, other)
+		}
+	}
+
+	var plan []FileRename
+	move := func(oldPath, newPath string) {
+		plan = append(plan, FileRename{OldPath: oldPath, NewPath: newPath})
+		delete(occupied, oldPath)
+		occupied[newPath] = true
+	}
+	for len(pending) > 0 {
+		olds := make([]string, 0, len(pending))
+		for old := range pending {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+
+		progress := false
+		for _, old := range olds {
+			if _, blocked := pathConflict(occupied, pending[old]); !blocked {
+				move(old, pending[old])
+				delete(pending, old)
+				progress = true
+			}
+		}
+		if progress {
+			continue
+		}
+
+		// every remaining rename waits for another one, follow the paths in
+		// the way until they lead in a cycle and park one file of it
+		seen := map[string]bool{}
+		old := olds[0]
+		for !seen[old] {
+			seen[old] = true
+			blocker, _ := pathConflict(occupied, pending[old])
+			if _, ok := pending[blocker]; !ok {
+				return nil, errors.Errorf("renaming %q to %q is blocked by %q", old, pending[old], blocker)
+			}
+			old = blocker
+		}
+		tmp := old + renameTempSuffix
+		for i := 1; occupied[tmp] || targets[tmp] != ""; i++ {
+			tmp = old + renameTempSuffix + strconv.Itoa(i)
+		}
+		move(old, tmp)
+		pending[tmp] = pending[old]
+		delete(pending, old)
+	}
+	return plan, nil
+}
+
+// pathConflict returns a path of names which keeps name from being used as
+// a file: name itself, one of its folders, or a file inside it.
+func pathConflict(names map[string]bool, name string) (string, bool) {
+	if names[name] {
+		return name, true
+	}
+	for i := strings.LastIndexByte(name, '/'); i > 0; i = strings.LastIndexByte(name[:i], '/') {
+		if names[name[:i]] {
+			return name[:i], true
+		}
+	}
+	var inside []string
+	for other := range names {
+		if strings.HasPrefix(other, name+"/") {
+			inside = append(inside, other)
+		}
+	}
+	if len(inside) == 0 {
+		return "", false
+	}
+	sort.Strings(inside)
+	return inside[0], true
+}
+
+// RenameFiles renames the files of a torrent to fn(TorrentFile.Name) in the
+// order of PlanFileRenames. It stops at the first failing rename and
+// returns the renames which were applied.
+func (t *torrentsApi) RenameFiles(torrent *Torrent, fn func(name string) string) ([]FileRename, error) {
+	return t.RenameFilesContext(context.Background(), torrent, fn)
+}
+
+// RenameFilesContext is like RenameFiles but carries ctx for cancellation.
+func (t *torrentsApi) RenameFilesContext(ctx context.Context, torrent *Torrent, fn func(name string) string) ([]FileRename, error) {
+	return t.RenameFilesByHashContext(ctx, torrent.Hash, fn)
+}
+
+func (t *torrentsApi) RenameFilesByHash(hash string, fn func(name string) string) ([]FileRename, error) {
+	return t.RenameFilesByHashContext(context.Background(), hash, fn)
+}
+
+// RenameFilesByHashContext is like RenameFilesByHash but carries ctx for cancellation.
+func (t *torrentsApi) RenameFilesByHashContext(ctx context.Context, hash string, fn func(name string) string) ([]FileRename, error) {
+	files, err := t.GetAllTorrentFilesByHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanFileRenames(files, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, step := range plan {
+		if err := t.RenameFileByHashContext(ctx, hash, step.OldPath, step.NewPath); err != nil {
+			return plan[:i], errors.Wrapf(err, "rename %q to %q", step.OldPath, step.NewPath)
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,177 @@
+package qbittorrent_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPlanFileRenames(t *testing.T) {
+	files := func(names ...string) []*TorrentFile {
+		var tfs []*TorrentFile
+		for i, name := range names {
+			tfs = append(tfs, &TorrentFile{Index: i, Name: name})
+		}
+		return tfs
+	}
+	mapping := func(m map[string]string) func(string) string {
+		return func(name string) string { return m[name] }
+	}
+	tests := []struct {
+		name    string
+		files   []*TorrentFile
+		fn      func(string) string
+		want    []FileRename
+		wantErr bool
+	}{
+		{
+			name:  "chain",
+			files: files("a", "b"),
+			fn:    mapping(map[string]string{"a": "b", "b": "c"}),
+			want:  []FileRename{{"b", "c"}, {"a", "b"}},
+		},
+		{
+			name:  "swap",
+			files: files("a", "b"),
+			fn:    mapping(map[string]string{"a": "b", "b": "a"}),
+			want: []FileRename{
+				{"a", "a" + renameTempSuffix},
+				{"b", "a"},
+				{"a" + renameTempSuffix, "b"},
+			},
+		},
+		{
+			name:    "same target",
+			files:   files("a", "b"),
+			fn:      mapping(map[string]string{"a": "c", "b": "c"}),
+			wantErr: true,
+		},
+		{
+			name:    "existing file",
+			files:   files("a", "b"),
+			fn:      mapping(map[string]string{"a": "b"}),
+			wantErr: true,
+		},
+		{
+			name:    "target is a folder",
+			files:   files("a", "dir/x"),
+			fn:      mapping(map[string]string{"a": "dir"}),
+			wantErr: true,
+		},
+		{
+			name:    "target under a file",
+			files:   files("a", "b"),
+			fn:      mapping(map[string]string{"a": "b/c"}),
+			wantErr: true,
+		},
+		{
+			name:    "targets nested",
+			files:   files("a", "b"),
+			fn:      mapping(map[string]string{"a": "c", "b": "c/d"}),
+			wantErr: true,
+		},
+		{
+			name:    "own folder",
+			files:   files("dir/x"),
+			fn:      mapping(map[string]string{"dir/x": "dir"}),
+			wantErr: true,
+		},
+		{
+			name:  "folder freed first",
+			files: files("a", "dir/x"),
+			fn:    mapping(map[string]string{"a": "dir", "dir/x": "y"}),
+			want:  []FileRename{{"dir/x", "y"}, {"a", "dir"}},
+		},
+		{
+			name:  "into own name",
+			files: files("a"),
+			fn:    mapping(map[string]string{"a": "a/b"}),
+			want:  []FileRename{{"a", "a" + renameTempSuffix}, {"a" + renameTempSuffix, "a/b"}},
+		},
+		{
+			name:  "folder cycle",
+			files: files("a", "dir/x"),
+			fn:    mapping(map[string]string{"a": "dir", "dir/x": "a"}),
+			want:  []FileRename{{"a", "a" + renameTempSuffix}, {"dir/x", "a"}, {"a" + renameTempSuffix, "dir"}},
+		},
+		{
+			name:  "unchanged",
+			files: files("a", "b"),
+			fn:    func(name string) string { return name },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanFileRenames(tt.files, tt.fn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanFileRenames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanFileRenames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameFiles(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	names := map[string]bool{"Show/S01E01.mkv": true, "Show/S01E02.mkv": true, "Show/S01E02.srt": true}
+	srv.handle("torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var tfs []*TorrentFile
+		for name := range names {
+			tfs = append(tfs, &TorrentFile{Name: name})
+		}
+		sort.Slice(tfs, func(i, j int) bool { return tfs[i].Name < tfs[j].Name })
+		json.NewEncoder(w).Encode(tfs)
+	})
+	srv.handle("torrents/renameFile", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		r.ParseForm()
+		oldPath, newPath := r.PostForm.Get("oldPath"), r.PostForm.Get("newPath")
+		if !names[oldPath] || names[newPath] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(names, oldPath)
+		names[newPath] = true
+	})
+	var renamed string
+	srv.handle("torrents/rename", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		renamed = r.PostForm.Get("name")
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	episode := regexp.MustCompile(`S01E(\d+)`)
+	plan, err := c.RenameFilesByHash("aaa", func(name string) string {
+		return episode.ReplaceAllString(name, "Season 1 - $1")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 3 {
+		t.Errorf("unexpected plan %v", plan)
+	}
+	var got []string
+	for name := range names {
+		got = append(got, name)
+	}
+	sort.Strings(got)
+	if want := "Show/Season 1 - 01.mkv|Show/Season 1 - 02.mkv|Show/Season 1 - 02.srt"; strings.Join(got, "|") != want {
+		t.Errorf("files %v, want %s", got, want)
+	}
+
+	if err := c.RenameTorrent(&Torrent{Hash: "aaa"}, "Show S01"); err != nil || renamed != "Show S01" {
+		t.Errorf("renamed to %q, %v", renamed, err)
+	}
+}
@@ -0,0 +1,219 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	actionGetDownloadLimit torrentsAction = "downloadLimit"
+	actionSetDownloadLimit torrentsAction = "setDownloadLimit"
+	actionGetUploadLimit   torrentsAction = "uploadLimit"
+	actionSetUploadLimit   torrentsAction = "setUploadLimit"
+	actionSetShareLimits   torrentsAction = "setShareLimits"
+)
+
+// RatioLimit is the share ratio after which a torrent stops seeding.
+type RatioLimit float64
+
+// SeedingTimeLimit is a seeding time limit in minutes.
+type SeedingTimeLimit int
+
+const (
+	RatioLimitGlobal    RatioLimit = -2 // use the global share ratio limit
+	RatioLimitUnlimited RatioLimit = -1 // no share ratio limit
+
+	SeedingTimeLimitGlobal    SeedingTimeLimit = -2 // use the global seeding time limit
+	SeedingTimeLimitUnlimited SeedingTimeLimit = -1 // no seeding time limit
+)
+
+func (l RatioLimit) String() string {
+	return strconv.FormatFloat(float64(l), 'f', -1, 64)
+}
+
+func (l SeedingTimeLimit) String() string {
+	return strconv.Itoa(int(l))
+}
+
+// ShareLimits are the limits set by SetShareLimits. Like in
+// DownloadBaseConfig, a zero field is not set and keeps the global setting.
+type ShareLimits struct {
+	Ratio               RatioLimit
+	SeedingTime         SeedingTimeLimit
+	InactiveSeedingTime SeedingTimeLimit // used since qBittorrent 4.6
+}
+
+// GlobalShareLimits returns limits which follow the global settings, the
+// same as the zero ShareLimits.
+func GlobalShareLimits() ShareLimits {
+	return ShareLimits{
+		Ratio:               RatioLimitGlobal,
+		SeedingTime:         SeedingTimeLimitGlobal,
+		InactiveSeedingTime: SeedingTimeLimitGlobal,
+	}
+}
+
+// GetDownloadLimit Get the download limits of torrents in bytes/s, keyed by hash.
+// 0 means no limit.
+func (t *torrentsApi) GetDownloadLimit(ts ...*Torrent) (map[string]int64, error) {
+	return t.GetDownloadLimitContext(context.Background(), ts...)
+}
+
+// GetDownloadLimitContext is like GetDownloadLimit but carries ctx for cancellation.
+func (t *torrentsApi) GetDownloadLimitContext(ctx context.Context, ts ...*Torrent) (map[string]int64, error) {
+	return t.GetDownloadLimitByHashesContext(ctx, hashesOf(ts))
+}
+
+func (t *torrentsApi) GetDownloadLimitByHashes(hashes []string) (map[string]int64, error) {
+	return t.GetDownloadLimitByHashesContext(context.Background(), hashes)
+}
+
+// GetDownloadLimitByHashesContext is like GetDownloadLimitByHashes but carries ctx for cancellation.
+func (t *torrentsApi) GetDownloadLimitByHashesContext(ctx context.Context, hashes []string) (map[string]int64, error) {
+	return t.getLimits(ctx, actionGetDownloadLimit, hashes)
+}
+
+// SetDownloadLimit Set the download limit of torrents in bytes/s, 0 for no limit.
+func (t *torrentsApi) SetDownloadLimit(limit int64, ts ...*Torrent) error {
+	return t.SetDownloadLimitContext(context.Background(), limit, ts...)
+}
+
+// SetDownloadLimitContext is like SetDownloadLimit but carries ctx for cancellation.
+func (t *torrentsApi) SetDownloadLimitContext(ctx context.Context, limit int64, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.actionByParams(ctx, newAction(actionSetDownloadLimit).withHashes(ts).setParam("limit", formatSpeedLimit(limit)))
+}
+
+func (t *torrentsApi) SetDownloadLimitByHashes(limit int64, hashes []string) error {
+	return t.SetDownloadLimitByHashesContext(context.Background(), limit, hashes)
+}
+
+// SetDownloadLimitByHashesContext is like SetDownloadLimitByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetDownloadLimitByHashesContext(ctx context.Context, limit int64, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionSetDownloadLimit).setParam("limit", formatSpeedLimit(limit)))
+}
+
+// GetUploadLimit Get the upload limits of torrents in bytes/s, keyed by hash.
+// 0 means no limit.
+func (t *torrentsApi) GetUploadLimit(ts ...*Torrent) (map[string]int64, error) {
+	return t.GetUploadLimitContext(context.Background(), ts...)
+}
+
+// GetUploadLimitContext is like GetUploadLimit but carries ctx for cancellation.
+func (t *torrentsApi) GetUploadLimitContext(ctx context.Context, ts ...*Torrent) (map[string]int64, error) {
+	return t.GetUploadLimitByHashesContext(ctx, hashesOf(ts))
+}
+
+func (t *torrentsApi) GetUploadLimitByHashes(hashes []string) (map[string]int64, error) {
+	return t.GetUploadLimitByHashesContext(context.Background(), hashes)
+}
+
+// GetUploadLimitByHashesContext is like GetUploadLimitByHashes but carries ctx for cancellation.
+func (t *torrentsApi) GetUploadLimitByHashesContext(ctx context.Context, hashes []string) (map[string]int64, error) {
+	return t.getLimits(ctx, actionGetUploadLimit, hashes)
+}
+
+// SetUploadLimit Set the upload limit of torrents in bytes/s, 0 for no limit.
+func (t *torrentsApi) SetUploadLimit(limit int64, ts ...*Torrent) error {
+	return t.SetUploadLimitContext(context.Background(), limit, ts...)
+}
+
+// SetUploadLimitContext is like SetUploadLimit but carries ctx for cancellation.
+func (t *torrentsApi) SetUploadLimitContext(ctx context.Context, limit int64, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.actionByParams(ctx, newAction(actionSetUploadLimit).withHashes(ts).setParam("limit", formatSpeedLimit(limit)))
+}
+
+func (t *torrentsApi) SetUploadLimitByHashes(limit int64, hashes []string) error {
+	return t.SetUploadLimitByHashesContext(context.Background(), limit, hashes)
+}
+
+// SetUploadLimitByHashesContext is like SetUploadLimitByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetUploadLimitByHashesContext(ctx context.Context, limit int64, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionSetUploadLimit).setParam("limit", formatSpeedLimit(limit)))
+}
+
+// SetShareLimits Set the share limits of torrents.
+func (t *torrentsApi) SetShareLimits(limits ShareLimits, ts ...*Torrent) error {
+	return t.SetShareLimitsContext(context.Background(), limits, ts...)
+}
+
+// SetShareLimitsContext is like SetShareLimits but carries ctx for cancellation.
+func (t *torrentsApi) SetShareLimitsContext(ctx context.Context, limits ShareLimits, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.SetShareLimitsByHashesContext(ctx, limits, hashesOf(ts))
+}
+
+func (t *torrentsApi) SetShareLimitsByHashes(limits ShareLimits, hashes []string) error {
+	return t.SetShareLimitsByHashesContext(context.Background(), limits, hashes)
+}
+
+// SetShareLimitsByHashesContext is like SetShareLimitsByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetShareLimitsByHashesContext(ctx context.Context, limits ShareLimits, hashes []string) error {
+	return t.actionByHashes(ctx, hashes, newAction(actionSetShareLimits).
+		setParam("ratioLimit", limits.Ratio.orGlobal().String()).
+		setParam("seedingTimeLimit", limits.SeedingTime.orGlobal().String()).
+		setParam("inactiveSeedingTimeLimit", limits.InactiveSeedingTime.orGlobal().String()))
+}
+
+// orGlobal maps an unset limit to RatioLimitGlobal, the server needs all
+// limits of setShareLimits.
+func (l RatioLimit) orGlobal() RatioLimit {
+	if l == 0 {
+		return RatioLimitGlobal
+	}
+	return l
+}
+
+// orGlobal maps an unset limit to SeedingTimeLimitGlobal.
+func (l SeedingTimeLimit) orGlobal() SeedingTimeLimit {
+	if l == 0 {
+		return SeedingTimeLimitGlobal
+	}
+	return l
+}
+
+func (t *torrentsApi) getLimits(ctx context.Context, action torrentsAction, hashes []string) (map[string]int64, error) {
+	if len(hashes) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	_, body, err := t.postForTorrent(ctx, action, map[string]string{
+		"hashes": strings.Join(hashes, "|"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var limits map[string]int64
+	if err = json.Unmarshal(body, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func formatSpeedLimit(limit int64) string {
+	if limit < 0 {
+		limit = 0
+	}
+	return strconv.FormatInt(limit, 10)
+}
+
+func hashesOf(ts []*Torrent) []string {
+	hashes := make([]string, 0, len(ts))
+	for _, torrent := range ts {
+		hashes = append(hashes, torrent.Hash)
+	}
+	return hashes
+}
@@ -0,0 +1,68 @@
+package qbittorrent_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestTorrentLimits(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	posted := map[string]url.Values{}
+	record := func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		posted[r.URL.Path] = r.PostForm
+	}
+	srv.handle("torrents/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		record(w, r)
+		w.Write([]byte(`{"aaa":1048576,"bbb":0}`))
+	})
+	srv.handle("torrents/setUploadLimit", record)
+	srv.handle("torrents/setShareLimits", record)
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	limits, err := c.GetDownloadLimit(&Torrent{Hash: "aaa"}, &Torrent{Hash: "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits["aaa"] != 1048576 || limits["bbb"] != 0 || posted["/api/v2/torrents/downloadLimit"].Get("hashes") != "aaa|bbb" {
+		t.Errorf("unexpected limits %v, posted %v", limits, posted)
+	}
+
+	if err := c.SetUploadLimitByHashes(2048, []string{"aaa"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := posted["/api/v2/torrents/setUploadLimit"]; got.Get("limit") != "2048" || got.Get("hashes") != "aaa" {
+		t.Errorf("posted %v", got)
+	}
+
+	limitsCfg := GlobalShareLimits()
+	limitsCfg.Ratio = 1.5
+	limitsCfg.InactiveSeedingTime = SeedingTimeLimitUnlimited
+	if err := c.SetShareLimits(limitsCfg, &Torrent{Hash: "aaa"}); err != nil {
+		t.Fatal(err)
+	}
+	got := posted["/api/v2/torrents/setShareLimits"]
+	if got.Get("ratioLimit") != "1.5" || got.Get("seedingTimeLimit") != "-2" || got.Get("inactiveSeedingTimeLimit") != "-1" {
+		t.Errorf("posted %v", got)
+	}
+
+	// unset limits keep the global setting instead of limiting to 0
+	if err := c.SetShareLimitsByHashes(ShareLimits{Ratio: 2}, []string{"aaa"}); err != nil {
+		t.Fatal(err)
+	}
+	got = posted["/api/v2/torrents/setShareLimits"]
+	if got.Get("ratioLimit") != "2" || got.Get("seedingTimeLimit") != "-2" || got.Get("inactiveSeedingTimeLimit") != "-2" {
+		t.Errorf("posted %v", got)
+	}
+
+	var torrent Torrent
+	if err := json.Unmarshal([]byte(`{"ratio_limit":-2,"max_ratio":0.5,"seeding_time_limit":-1}`), &torrent); err != nil {
+		t.Fatal(err)
+	}
+	if torrent.RatioLimit != RatioLimitGlobal || torrent.SeedingTimeLimit != SeedingTimeLimitUnlimited {
+		t.Errorf("unexpected limits %+v", torrent)
+	}
+}
@@ -0,0 +1,243 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	actionSetLocation     torrentsAction = "setLocation"
+	actionSetSavePath     torrentsAction = "setSavePath"
+	actionSetDownloadPath torrentsAction = "setDownloadPath"
+)
+
+// SetLocation Move torrents to location, the data is moved in the
+// background while the torrents are in StateMoving.
+// The server answers ErrForbidden if location is not writable and
+// ErrConflict if it cannot be created.
+func (t *torrentsApi) SetLocation(location string, ts ...*Torrent) error {
+	return t.SetLocationContext(context.Background(), location, ts...)
+}
+
+// SetLocationContext is like SetLocation but carries ctx for cancellation.
+func (t *torrentsApi) SetLocationContext(ctx context.Context, location string, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.SetLocationByHashesContext(ctx, location, hashesOf(ts))
+}
+
+func (t *torrentsApi) SetLocationByHashes(location string, hashes []string) error {
+	return t.SetLocationByHashesContext(context.Background(), location, hashes)
+}
+
+// SetLocationByHashesContext is like SetLocationByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetLocationByHashesContext(ctx context.Context, location string, hashes []string) error {
+	if location == "" {
+		return errors.New("empty location")
+	}
+
+	return t.actionByHashes(ctx, hashes, newAction(actionSetLocation).setParam("location", location))
+}
+
+// SetSavePath Change the save path of torrents. Unlike SetLocation, the
+// data of complete torrents is not moved.
+func (t *torrentsApi) SetSavePath(path string, ts ...*Torrent) error {
+	return t.SetSavePathContext(context.Background(), path, ts...)
+}
+
+// SetSavePathContext is like SetSavePath but carries ctx for cancellation.
+func (t *torrentsApi) SetSavePathContext(ctx context.Context, path string, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.SetSavePathByHashesContext(ctx, path, hashesOf(ts))
+}
+
+func (t *torrentsApi) SetSavePathByHashes(path string, hashes []string) error {
+	return t.SetSavePathByHashesContext(context.Background(), path, hashes)
+}
+
+// SetSavePathByHashesContext is like SetSavePathByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetSavePathByHashesContext(ctx context.Context, path string, hashes []string) error {
+	return t.setPath(ctx, actionSetSavePath, path, hashes)
+}
+
+// SetDownloadPath Change the path of incomplete torrents.
+func (t *torrentsApi) SetDownloadPath(path string, ts ...*Torrent) error {
+	return t.SetDownloadPathContext(context.Background(), path, ts...)
+}
+
+// SetDownloadPathContext is like SetDownloadPath but carries ctx for cancellation.
+func (t *torrentsApi) SetDownloadPathContext(ctx context.Context, path string, ts ...*Torrent) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return t.SetDownloadPathByHashesContext(ctx, path, hashesOf(ts))
+}
+
+func (t *torrentsApi) SetDownloadPathByHashes(path string, hashes []string) error {
+	return t.SetDownloadPathByHashesContext(context.Background(), path, hashes)
+}
+
+// SetDownloadPathByHashesContext is like SetDownloadPathByHashes but carries ctx for cancellation.
+func (t *torrentsApi) SetDownloadPathByHashesContext(ctx context.Context, path string, hashes []string) error {
+	return t.setPath(ctx, actionSetDownloadPath, path, hashes)
+}
+
+// setPath calls setSavePath or setDownloadPath, which take the hashes as "id".
+func (t *torrentsApi) setPath(ctx context.Context, action torrentsAction, path string, hashes []string) error {
+	if err := t.client.requireFeatures(ctx, featureDownloadPath); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(action).
+		setParam("id", strings.Join(hashes, "|")).
+		setParam("path", path))
+}
+
+// MoveConfig configures MoveTorrents.
+type MoveConfig struct {
+	PollInterval time.Duration // interval to poll the torrent states, 1s by default
+	Timeout      time.Duration // give up after this long, no timeout by default
+}
+
+const (
+	defaultMovePollInterval = time.Second
+	// polls a torrent may stay out of StateMoving at the old path before
+	// the move is considered ignored by the server
+	moveSettlePolls = 3
+	// consecutive failed polls before MoveTorrents gives up
+	maxMovePollErrors = 5
+)
+
+// MoveResult is the outcome of MoveTorrents for one torrent.
+type MoveResult struct {
+	Hash     string
+	SavePath string // save path reported by the server when the torrent was last seen
+	Err      error  // nil if the torrent arrived at the location
+}
+
+// MoveTorrents moves the torrents to location with SetLocation and waits
+// until none of them is in StateMoving any more. A torrent which ends in an
+// error state, disappears, ends up at a save path other than location, or
+// is still moving at the timeout reports an error in its result. Failed
+// polls are retried. The returned error is only set if the move could not
+// be started.
+func (t *torrentsApi) MoveTorrents(location string, hashes []string, mc MoveConfig) ([]*MoveResult, error) {
+	return t.MoveTorrentsContext(context.Background(), location, hashes, mc)
+}
+
+// MoveTorrentsContext is like MoveTorrents but carries ctx for cancellation.
+func (t *torrentsApi) MoveTorrentsContext(ctx context.Context, location string, hashes []string, mc MoveConfig) ([]*MoveResult, error) {
+	hashes = dedupe(hashes)
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	if mc.PollInterval <= 0 {
+		mc.PollInterval = defaultMovePollInterval
+	}
+	if mc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, mc.Timeout)
+		defer cancel()
+	}
+
+	if err := t.SetLocationByHashesContext(ctx, location, hashes); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*MoveResult, len(hashes))
+	pending := map[string]bool{}
+	for _, hash := range hashes {
+		results[hash] = &MoveResult{Hash: hash}
+		pending[hash] = true
+	}
+
+	// the server may not have switched a torrent to StateMoving yet when it
+	// is polled first, so a torrent at another path only finishes once it
+	// was seen moving or after moveSettlePolls polls
+	seenMoving := map[string]bool{}
+	idlePolls := map[string]int{}
+	var pollErrs int
+	var lastErr error
+	err := poll(ctx, mc.PollInterval, func() (bool, error) {
+		ts, err := t.TorrentsContext(ctx, &Filter{Hashes: keys(pending)})
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			if pollErrs++; pollErrs >= maxMovePollErrors {
+				return false, err
+			}
+			lastErr = err
+			return false, nil
+		}
+		pollErrs, lastErr = 0, nil
+
+		seen := map[string]bool{}
+		for _, torrent := range ts {
+			if !pending[torrent.Hash] {
+				continue
+			}
+			seen[torrent.Hash] = true
+			result := results[torrent.Hash]
+			result.SavePath = torrent.SavePath
+			switch {
+			case torrent.State == StateMoving:
+				seenMoving[torrent.Hash] = true
+			case torrent.IsErrored():
+				result.Err = errors.Errorf("torrent %s: state %s after move", torrent.Hash, torrent.State)
+				delete(pending, torrent.Hash)
+			case samePath(torrent.SavePath, location):
+				delete(pending, torrent.Hash)
+			default:
+				if idlePolls[torrent.Hash]++; seenMoving[torrent.Hash] || idlePolls[torrent.Hash] >= moveSettlePolls {
+					result.Err = errors.Errorf("torrent %s: save path is %q after move, not %q", torrent.Hash, torrent.SavePath, location)
+					delete(pending, torrent.Hash)
+				}
+			}
+		}
+		for hash := range pending {
+			if !seen[hash] {
+				results[hash].Err = errors.Errorf("torrent %s: not found", hash)
+				delete(pending, hash)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		for hash := range pending {
+			if lastErr != nil {
+				results[hash].Err = errors.Wrapf(err, "torrent %s: waiting for move, last poll: %v", hash, lastErr)
+			} else {
+				results[hash].Err = errors.Wrapf(err, "torrent %s: waiting for move", hash)
+			}
+		}
+	}
+
+	res := make([]*MoveResult, 0, len(hashes))
+	for _, hash := range hashes {
+		res = append(res, results[hash])
+	}
+	return res, nil
+}
+
+// samePath compares paths reported by qBittorrent, ignoring trailing separators.
+func samePath(a, b string) bool {
+	return strings.TrimRight(a, `/\`) == strings.TrimRight(b, `/\`)
+}
+
+func keys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
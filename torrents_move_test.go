@@ -0,0 +1,88 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMoveTorrents(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	polls := 0
+	var location, moved string
+	srv.handle("torrents/setLocation", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		location, moved = r.PostForm.Get("location"), r.PostForm.Get("hashes")
+	})
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+		if polls == 2 {
+			http.Error(w, "busy", http.StatusInternalServerError)
+			return
+		}
+		// aaa is moving for two polls, bbb fails, ccc is unknown, ddd keeps
+		// moving, eee ignores the move and fff ends up at a resolved path
+		all := map[string]*Torrent{
+			"aaa": {Hash: "aaa", State: StateMoving, SavePath: "/old"},
+			"bbb": {Hash: "bbb", State: StateError, SavePath: "/old"},
+			"ddd": {Hash: "ddd", State: StateMoving, SavePath: "/old"},
+			"eee": {Hash: "eee", State: StateUploading, SavePath: "/old"},
+			"fff": {Hash: "fff", State: StateMoving, SavePath: "/old"},
+		}
+		if polls > 3 {
+			all["aaa"] = &Torrent{Hash: "aaa", State: StateUploading, SavePath: "/new/"}
+			all["fff"] = &Torrent{Hash: "fff", State: StateUploading, SavePath: "/mnt/new"}
+		}
+		ts := []*Torrent{}
+		for _, hash := range strings.Split(r.FormValue("hashes"), "|") {
+			if torrent, ok := all[hash]; ok {
+				ts = append(ts, torrent)
+			}
+		}
+		json.NewEncoder(w).Encode(ts)
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	results, err := c.MoveTorrents("/new", []string{"aaa", "bbb", "ccc", "ddd", "eee", "fff"}, MoveConfig{
+		PollInterval: 5 * time.Millisecond,
+		Timeout:      200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location != "/new" || moved != "aaa|bbb|ccc|ddd|eee|fff" {
+		t.Errorf("moved %q to %q", moved, location)
+	}
+	if len(results) != 6 {
+		t.Fatalf("got %d results", len(results))
+	}
+	if results[0].Err != nil || results[0].SavePath != "/new/" {
+		t.Errorf("aaa: %+v", results[0])
+	}
+	if results[1].Err == nil || results[2].Err == nil {
+		t.Errorf("bbb: %+v, ccc: %+v", results[1], results[2])
+	}
+	if !errors.Is(results[3].Err, context.DeadlineExceeded) {
+		t.Errorf("ddd: %+v", results[3])
+	}
+	if results[4].Err == nil || results[4].SavePath != "/old" || errors.Is(results[4].Err, context.DeadlineExceeded) {
+		t.Errorf("eee: %+v", results[4])
+	}
+	if results[5].Err == nil || results[5].SavePath != "/mnt/new" || errors.Is(results[5].Err, context.DeadlineExceeded) {
+		t.Errorf("fff: %+v", results[5])
+	}
+
+	if _, err := c.MoveTorrents("", []string{"aaa"}, MoveConfig{}); err == nil {
+		t.Error("expected an error for an empty location")
+	}
+}
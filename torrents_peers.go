@@ -0,0 +1,64 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	actionAddPeers torrentsAction = "addPeers"
+)
+
+// AddPeersResult is the outcome of AddPeers for one torrent.
+type AddPeersResult struct {
+	Added  int `json:"added"`
+	Failed int `json:"failed"`
+}
+
+// AddPeers Add peers to one or more torrents.
+// The results are keyed by hash, older servers don't report any.
+func (t *torrentsApi) AddPeers(peers []PeerAddr, ts ...*Torrent) (map[string]AddPeersResult, error) {
+	return t.AddPeersContext(context.Background(), peers, ts...)
+}
+
+// AddPeersContext is like AddPeers but carries ctx for cancellation.
+func (t *torrentsApi) AddPeersContext(ctx context.Context, peers []PeerAddr, ts ...*Torrent) (map[string]AddPeersResult, error) {
+	if len(ts) == 0 {
+		return nil, nil
+	}
+
+	return t.AddPeersByHashesContext(ctx, peers, hashesOf(ts))
+}
+
+func (t *torrentsApi) AddPeersByHashes(peers []PeerAddr, hashes []string) (map[string]AddPeersResult, error) {
+	return t.AddPeersByHashesContext(context.Background(), peers, hashes)
+}
+
+// AddPeersByHashesContext is like AddPeersByHashes but carries ctx for cancellation.
+func (t *torrentsApi) AddPeersByHashesContext(ctx context.Context, peers []PeerAddr, hashes []string) (map[string]AddPeersResult, error) {
+	if len(peers) == 0 || len(hashes) == 0 {
+		return nil, nil
+	}
+	addrs, err := joinPeerAddrs(peers)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := t.client.postForTorrent(ctx, actionAddPeers, map[string]string{
+		"hashes": strings.Join(hashes, "|"),
+		"peers":  addrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil, nil
+	}
+
+	var results map[string]AddPeersResult
+	if err = json.Unmarshal(body, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,245 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	actionGetPieceStates torrentsAction = "pieceStates"
+	actionGetPieceHashes torrentsAction = "pieceHashes"
+)
+
+type PieceState int
+
+const (
+	PieceNotDownloaded PieceState = 0 // Not downloaded yet
+	PieceDownloading   PieceState = 1 // Now downloading
+	PieceDownloaded    PieceState = 2 // Already downloaded
+)
+
+func (s PieceState) String() string {
+	switch s {
+	case PieceNotDownloaded:
+		return "not downloaded"
+	case PieceDownloading:
+		return "downloading"
+	case PieceDownloaded:
+		return "downloaded"
+	}
+	return "unknown"
+}
+
+// GetTorrentPieceStates
+// Get the states of all pieces of a torrent, in piece order.
+func (t *torrentsApi) GetTorrentPieceStates(torrent *Torrent) ([]PieceState, error) {
+	return t.GetTorrentPieceStatesContext(context.Background(), torrent)
+}
+
+// GetTorrentPieceStatesContext is like GetTorrentPieceStates but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPieceStatesContext(ctx context.Context, torrent *Torrent) ([]PieceState, error) {
+	return t.GetTorrentPieceStatesByHashContext(ctx, torrent.Hash)
+}
+
+// GetTorrentPieceStatesByHash
+// Get the states of all pieces of a torrent, in piece order.
+func (t *torrentsApi) GetTorrentPieceStatesByHash(hash string) ([]PieceState, error) {
+	return t.GetTorrentPieceStatesByHashContext(context.Background(), hash)
+}
+
+// GetTorrentPieceStatesByHashContext is like GetTorrentPieceStatesByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPieceStatesByHashContext(ctx context.Context, hash string) ([]PieceState, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetPieceStates, map[string]string{
+		"hash": hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var states []PieceState
+	if err = json.Unmarshal(body, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// GetTorrentPieceHashes
+// Get the hex encoded hashes of all pieces of a torrent, in piece order.
+func (t *torrentsApi) GetTorrentPieceHashes(torrent *Torrent) ([]string, error) {
+	return t.GetTorrentPieceHashesContext(context.Background(), torrent)
+}
+
+// GetTorrentPieceHashesContext is like GetTorrentPieceHashes but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPieceHashesContext(ctx context.Context, torrent *Torrent) ([]string, error) {
+	return t.GetTorrentPieceHashesByHashContext(ctx, torrent.Hash)
+}
+
+// GetTorrentPieceHashesByHash
+// Get the hex encoded hashes of all pieces of a torrent, in piece order.
+func (t *torrentsApi) GetTorrentPieceHashesByHash(hash string) ([]string, error) {
+	return t.GetTorrentPieceHashesByHashContext(context.Background(), hash)
+}
+
+// GetTorrentPieceHashesByHashContext is like GetTorrentPieceHashesByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPieceHashesByHashContext(ctx context.Context, hash string) ([]string, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetPieceHashes, map[string]string{
+		"hash": hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	if err = json.Unmarshal(body, &hashes); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// FilePieces are the pieces covering one file of a torrent.
+type FilePieces struct {
+	File       *TorrentFile
+	FirstPiece int          // index of the first piece, File.PieceRange[0]
+	States     []PieceState // states of the pieces File.PieceRange[0] to File.PieceRange[1]
+	Offset     int64        // start of the file in the torrent data (bytes), not counting hidden pad files
+}
+
+// MapFilePieces splits the piece states of a torrent by file, using
+// TorrentFile.PieceRange. Offsets are the sizes of the preceding files in
+// index order, qBittorrent doesn't list the BEP 47 pad files of v2 and hybrid
+// torrents. DownloadedRanges accounts for them. Servers before Web API 2.8.2 don't
+// report the index, the files are then taken in the order given.
+// Empty files, reported with a piece range [n, n-1], have no pieces.
+func MapFilePieces(files []*TorrentFile, states []PieceState) ([]*FilePieces, error) {
+	sorted := append([]*TorrentFile(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+
+	res := make([]*FilePieces, 0, len(sorted))
+	var offset int64
+	for _, file := range sorted {
+		if len(file.PieceRange) != 2 {
+			return nil, errors.Errorf("file %q: invalid piece range %v", file.Name, file.PieceRange)
+		}
+		first, last := file.PieceRange[0], file.PieceRange[1]
+		empty := last == first-1
+		if first < 0 || (last < first && !empty) || last >= len(states) || first > len(states) {
+			return nil, errors.Errorf("file %q: piece range %v out of %d pieces", file.Name, file.PieceRange, len(states))
+		}
+		res = append(res, &FilePieces{
+			File:       file,
+			FirstPiece: first,
+			States:     append([]PieceState(nil), states[first:last+1]...),
+			Offset:     offset,
+		})
+		offset += file.Size
+	}
+	return res, nil
+}
+
+// Downloaded returns the number of downloaded pieces.
+func (fp *FilePieces) Downloaded() int {
+	n := 0
+	for _, state := range fp.States {
+		if state == PieceDownloaded {
+			n++
+		}
+	}
+	return n
+}
+
+// Completion returns the share of downloaded pieces (percentage/100).
+func (fp *FilePieces) Completion() float64 {
+	if len(fp.States) == 0 {
+		return 0
+	}
+	return float64(fp.Downloaded()) / float64(len(fp.States))
+}
+
+// ByteRange is a half-open range of bytes [Start, End).
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// DownloadedRanges returns the byte ranges of the file, relative to its
+// start, which are backed by downloaded pieces of pieceSize bytes
+// (TorrentProperties.PieceSize).
+//
+// In torrents with hidden pad files a file starts on a piece boundary after
+// Offset, its start is then taken from FirstPiece. An error is returned if
+// Offset lies past the first piece of the file.
+func (fp *FilePieces) DownloadedRanges(pieceSize int64) ([]ByteRange, error) {
+	if pieceSize <= 0 {
+		return nil, errors.Errorf("invalid piece size %d", pieceSize)
+	}
+	if len(fp.States) == 0 {
+		return nil, nil
+	}
+
+	offset := fp.Offset
+	if aligned := int64(fp.FirstPiece) * pieceSize; offset < aligned {
+		offset = aligned
+	} else if offset >= aligned+pieceSize {
+		return nil, errors.Errorf("file %q: offset %d is past its first piece %d", fp.File.Name, fp.Offset, fp.FirstPiece)
+	}
+
+	var ranges []ByteRange
+	for i, state := range fp.States {
+		if state != PieceDownloaded {
+			continue
+		}
+		piece := int64(fp.FirstPiece + i)
+		start := piece*pieceSize - offset
+		end := start + pieceSize
+		if start < 0 {
+			start = 0
+		}
+		if end > fp.File.Size {
+			end = fp.File.Size
+		}
+		if start >= end {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End == start {
+			ranges[n-1].End = end
+		} else {
+			ranges = append(ranges, ByteRange{Start: start, End: end})
+		}
+	}
+	return ranges, nil
+}
+
+// GetFilePieces
+// Get the piece states of every file of a torrent.
+func (t *torrentsApi) GetFilePieces(torrent *Torrent) ([]*FilePieces, error) {
+	return t.GetFilePiecesContext(context.Background(), torrent)
+}
+
+// GetFilePiecesContext is like GetFilePieces but carries ctx for cancellation.
+func (t *torrentsApi) GetFilePiecesContext(ctx context.Context, torrent *Torrent) ([]*FilePieces, error) {
+	return t.GetFilePiecesByHashContext(ctx, torrent.Hash)
+}
+
+// GetFilePiecesByHash
+// Get the piece states of every file of a torrent.
+func (t *torrentsApi) GetFilePiecesByHash(hash string) ([]*FilePieces, error) {
+	return t.GetFilePiecesByHashContext(context.Background(), hash)
+}
+
+// GetFilePiecesByHashContext is like GetFilePiecesByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetFilePiecesByHashContext(ctx context.Context, hash string) ([]*FilePieces, error) {
+	files, err := t.GetAllTorrentFilesByHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	states, err := t.GetTorrentPieceStatesByHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return MapFilePieces(files, states)
+}
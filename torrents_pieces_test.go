@@ -0,0 +1,98 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFilePieces(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	// piece size 10, a.mkv is bytes 0-24 (pieces 0-2), b.srt bytes 25-34 (pieces 2-3)
+	srv.handle("torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"index":1,"name":"b.srt","size":10,"piece_range":[2,3]},{"index":0,"name":"a.mkv","size":25,"piece_range":[0,2]}]`))
+	})
+	srv.handle("torrents/pieceStates", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[2,1,2,2]`))
+	})
+	srv.handle("torrents/pieceHashes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["8c9c36ba5b0a4fd2e6b17e9b1c0c2a2f6b3e5d10","0b2a9d8e5f3c6b7a4d1e0f9c8b7a6d5e4f3c2b1a"]`))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	hashes, err := c.GetTorrentPieceHashesByHash("aaa")
+	if err != nil || len(hashes) != 2 {
+		t.Fatalf("got %v, %v", hashes, err)
+	}
+
+	fps, err := c.GetFilePiecesByHash("aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fps) != 2 || fps[0].File.Name != "a.mkv" || fps[1].Offset != 25 {
+		t.Fatalf("unexpected file pieces %+v", fps)
+	}
+	if !reflect.DeepEqual(fps[0].States, []PieceState{PieceDownloaded, PieceDownloading, PieceDownloaded}) || fps[0].Downloaded() != 2 {
+		t.Errorf("unexpected states %v", fps[0].States)
+	}
+	if got, err := fps[0].DownloadedRanges(10); err != nil || !reflect.DeepEqual(got, []ByteRange{{0, 10}, {20, 25}}) {
+		t.Errorf("a.mkv ranges %v, %v", got, err)
+	}
+	if got, err := fps[1].DownloadedRanges(10); err != nil || !reflect.DeepEqual(got, []ByteRange{{0, 10}}) || fps[1].Completion() != 1 {
+		t.Errorf("b.srt ranges %v, %v", got, err)
+	}
+
+	// an empty file at the end and no indices, as before Web API 2.8.2
+	fps, err = MapFilePieces([]*TorrentFile{
+		{Name: "a.mkv", Size: 25, PieceRange: []int{0, 2}},
+		{Name: "b.srt", Size: 10, PieceRange: []int{2, 3}},
+		{Name: "empty", Size: 0, PieceRange: []int{4, 3}},
+	}, []PieceState{2, 2, 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fps[0].File.Name != "a.mkv" || fps[1].Offset != 25 || fps[2].Offset != 35 || len(fps[2].States) != 0 {
+		t.Errorf("unexpected file pieces %+v %+v %+v", fps[0], fps[1], fps[2])
+	}
+	if got, err := fps[2].DownloadedRanges(10); got != nil || err != nil {
+		t.Errorf("empty file ranges %v, %v", got, err)
+	}
+
+	if _, err := MapFilePieces([]*TorrentFile{{Name: "c", PieceRange: []int{0, 9}}}, make([]PieceState, 4)); err == nil {
+		t.Error("expected an error for a piece range out of bounds")
+	}
+}
+
+func TestFilePiecesPadded(t *testing.T) {
+	// piece size 10 with hidden pad files as in v2 and hybrid torrents:
+	// a.mkv is bytes 0-24, padded to 30, b.srt bytes 30-41, padded to 50,
+	// c.nfo bytes 50-52
+	fps, err := MapFilePieces([]*TorrentFile{
+		{Index: 0, Name: "a.mkv", Size: 25, PieceRange: []int{0, 2}},
+		{Index: 1, Name: "b.srt", Size: 12, PieceRange: []int{3, 4}},
+		{Index: 2, Name: "c.nfo", Size: 3, PieceRange: []int{5, 5}},
+	}, []PieceState{2, 2, 2, 0, 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range [][]ByteRange{{{0, 25}}, {{10, 12}}, {{0, 3}}} {
+		got, err := fps[i].DownloadedRanges(10)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s ranges %v, %v, want %v", fps[i].File.Name, got, err, want)
+		}
+	}
+
+	// an offset past the first piece doesn't match the piece range
+	fps, err = MapFilePieces([]*TorrentFile{
+		{Index: 0, Name: "a", Size: 25, PieceRange: []int{0, 2}},
+		{Index: 1, Name: "b", Size: 5, PieceRange: []int{1, 1}},
+	}, []PieceState{2, 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fps[1].DownloadedRanges(10); err == nil {
+		t.Error("expected an error for an offset past the first piece")
+	}
+}
@@ -0,0 +1,106 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	actionGetProperties torrentsAction = "properties"
+)
+
+type TorrentProperties struct {
+	Hash                   string  `json:"hash"`                     // Torrent hash (qBittorrent 4.4+)
+	InfoHashV1             string  `json:"infohash_v1"`              // Torrent v1 info hash
+	InfoHashV2             string  `json:"infohash_v2"`              // Torrent v2 info hash
+	Name                   string  `json:"name"`                     // Torrent name
+	SavePath               string  `json:"save_path"`                // Torrent save path
+	DownloadPath           string  `json:"download_path"`            // Torrent download path
+	CreationDate           int     `json:"creation_date"`            // Torrent creation date (Unix timestamp)
+	PieceSize              int64   `json:"piece_size"`               // Torrent piece size (bytes)
+	Comment                string  `json:"comment"`                  // Torrent comment
+	TotalWasted            int64   `json:"total_wasted"`             // Total data wasted for torrent (bytes)
+	TotalUploaded          int64   `json:"total_uploaded"`           // Total data uploaded for torrent (bytes)
+	TotalUploadedSession   int64   `json:"total_uploaded_session"`   // Total data uploaded this session (bytes)
+	TotalDownloaded        int64   `json:"total_downloaded"`         // Total data downloaded for torrent (bytes)
+	TotalDownloadedSession int64   `json:"total_downloaded_session"` // Total data downloaded this session (bytes)
+	UpLimit                int     `json:"up_limit"`                 // Torrent upload limit (bytes/s)
+	DlLimit                int     `json:"dl_limit"`                 // Torrent download limit (bytes/s)
+	TimeElapsed            int     `json:"time_elapsed"`             // Torrent elapsed time (seconds)
+	SeedingTime            int     `json:"seeding_time"`             // Torrent elapsed time while complete (seconds)
+	NbConnections          int     `json:"nb_connections"`           // Torrent connection count
+	NbConnectionsLimit     int     `json:"nb_connections_limit"`     // Torrent connection count limit
+	ShareRatio             float64 `json:"share_ratio"`              // Torrent share ratio
+	AdditionDate           int     `json:"addition_date"`            // When this torrent was added (Unix timestamp)
+	CompletionDate         int     `json:"completion_date"`          // Torrent completion date (Unix timestamp)
+	CreatedBy              string  `json:"created_by"`               // Torrent creator
+	DlSpeedAvg             int     `json:"dl_speed_avg"`             // Torrent average download speed (bytes/s)
+	DlSpeed                int     `json:"dl_speed"`                 // Torrent download speed (bytes/s)
+	Eta                    int     `json:"eta"`                      // Torrent ETA (seconds)
+	LastSeen               int     `json:"last_seen"`                // Last seen complete date (Unix timestamp)
+	Peers                  int     `json:"peers"`                    // Number of peers connected to
+	PeersTotal             int     `json:"peers_total"`              // Number of peers in the swarm
+	PiecesHave             int     `json:"pieces_have"`              // Number of pieces owned
+	PiecesNum              int     `json:"pieces_num"`               // Number of pieces of the torrent
+	Reannounce             int     `json:"reannounce"`               // Number of seconds until the next announce
+	Seeds                  int     `json:"seeds"`                    // Number of seeds connected to
+	SeedsTotal             int     `json:"seeds_total"`              // Number of seeds in the swarm
+	TotalSize              int64   `json:"total_size"`               // Torrent total size (bytes)
+	UpSpeedAvg             int     `json:"up_speed_avg"`             // Torrent average upload speed (bytes/s)
+	UpSpeed                int     `json:"up_speed"`                 // Torrent upload speed (bytes/s)
+	IsPrivate              bool    `json:"is_private"`               // True if torrent is from a private tracker
+	HasMetadata            bool    `json:"has_metadata"`             // False while a magnet link is fetching metadata
+}
+
+type torrentPropertiesJSON TorrentProperties
+
+// UnmarshalJSON also accepts "isPrivate", as reported before qBittorrent 5.0.
+func (p *TorrentProperties) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		*torrentPropertiesJSON
+		IsPrivate *bool `json:"isPrivate"`
+	}{torrentPropertiesJSON: (*torrentPropertiesJSON)(p)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.IsPrivate != nil {
+		p.IsPrivate = *raw.IsPrivate
+	}
+	return nil
+}
+
+// GetTorrentProperties
+// Get torrent generic properties.
+func (t *torrentsApi) GetTorrentProperties(torrent *Torrent) (*TorrentProperties, error) {
+	return t.GetTorrentPropertiesContext(context.Background(), torrent)
+}
+
+// GetTorrentPropertiesContext is like GetTorrentProperties but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPropertiesContext(ctx context.Context, torrent *Torrent) (*TorrentProperties, error) {
+	return t.GetTorrentPropertiesByHashContext(ctx, torrent.Hash)
+}
+
+// GetTorrentPropertiesByHash
+// Get torrent generic properties.
+func (t *torrentsApi) GetTorrentPropertiesByHash(hash string) (*TorrentProperties, error) {
+	return t.GetTorrentPropertiesByHashContext(context.Background(), hash)
+}
+
+// GetTorrentPropertiesByHashContext is like GetTorrentPropertiesByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentPropertiesByHashContext(ctx context.Context, hash string) (*TorrentProperties, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetProperties, map[string]string{
+		"hash": hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var props TorrentProperties
+	if err = json.Unmarshal(body, &props); err != nil {
+		return nil, err
+	}
+	if props.Hash == "" {
+		props.Hash = hash
+	}
+	return &props, nil
+}
@@ -0,0 +1,43 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetTorrentProperties(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.handle("torrents/properties", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("hash") {
+		case "aaa":
+			w.Write([]byte(`{"addition_date":1700000000,"comment":"Debian CD","created_by":"mktorrent","creation_date":1699990000,"piece_size":262144,"pieces_have":10,"pieces_num":2000,"total_wasted":16384,"reannounce":1500,"peers_total":42,"share_ratio":0.25,"isPrivate":true}`))
+		case "bbb":
+			w.Write([]byte(`{"hash":"bbb","is_private":true,"total_size":1073741824}`))
+		default:
+			http.Error(w, "", http.StatusNotFound)
+		}
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	p, err := c.GetTorrentPropertiesByHash("aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Hash != "aaa" || p.Comment != "Debian CD" || p.CreatedBy != "mktorrent" || p.PieceSize != 262144 ||
+		p.PiecesNum != 2000 || p.TotalWasted != 16384 || p.Reannounce != 1500 || p.PeersTotal != 42 || !p.IsPrivate {
+		t.Errorf("unexpected properties %+v", p)
+	}
+
+	p, err = c.GetTorrentProperties(&Torrent{Hash: "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsPrivate || p.TotalSize != 1073741824 {
+		t.Errorf("unexpected properties %+v", p)
+	}
+
+	if _, err := c.GetTorrentPropertiesByHash("ccc"); err == nil {
+		t.Error("expected an error for an unknown hash")
+	}
+}
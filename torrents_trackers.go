@@ -0,0 +1,199 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	actionGetTrackers    torrentsAction = "trackers"
+	actionAddTrackers    torrentsAction = "addTrackers"
+	actionEditTracker    torrentsAction = "editTracker"
+	actionRemoveTrackers torrentsAction = "removeTrackers"
+)
+
+type TrackerStatus int
+
+const (
+	TrackerDisabled     TrackerStatus = 0 // Tracker is disabled (used for DHT, PeX, and LSD)
+	TrackerNotContacted TrackerStatus = 1 // Tracker has not been contacted yet
+	TrackerWorking      TrackerStatus = 2 // Tracker has been contacted and is working
+	TrackerUpdating     TrackerStatus = 3 // Tracker is updating
+	TrackerNotWorking   TrackerStatus = 4 // Tracker has been contacted, but it is not working (or doesn't send proper replies)
+)
+
+func (s TrackerStatus) String() string {
+	switch s {
+	case TrackerDisabled:
+		return "disabled"
+	case TrackerNotContacted:
+		return "not contacted"
+	case TrackerWorking:
+		return "working"
+	case TrackerUpdating:
+		return "updating"
+	case TrackerNotWorking:
+		return "not working"
+	}
+	return "unknown"
+}
+
+type Tracker struct {
+	URL           string        `json:"url"`            // Tracker url, "** [DHT] **" etc. for the pseudo trackers
+	Status        TrackerStatus `json:"status"`         // Tracker status
+	Tier          int           `json:"tier"`           // Tracker priority tier, -1 for the pseudo trackers
+	NumPeers      int           `json:"num_peers"`      // Number of peers for current torrent, as reported by the tracker
+	NumSeeds      int           `json:"num_seeds"`      // Number of seeds for current torrent, as reported by the tracker
+	NumLeeches    int           `json:"num_leeches"`    // Number of leeches for current torrent, as reported by the tracker
+	NumDownloaded int           `json:"num_downloaded"` // Number of completed downloads for current torrent, as reported by the tracker
+	Msg           string        `json:"msg"`            // Tracker message
+}
+
+// IsPseudo reports whether the entry stands for DHT, PeX or LSD.
+func (tr *Tracker) IsPseudo() bool {
+	return strings.HasPrefix(tr.URL, "** [")
+}
+
+// GetTorrentTrackers
+// Get torrent trackers, including the DHT, PeX and LSD pseudo trackers.
+func (t *torrentsApi) GetTorrentTrackers(torrent *Torrent) ([]*Tracker, error) {
+	return t.GetTorrentTrackersContext(context.Background(), torrent)
+}
+
+// GetTorrentTrackersContext is like GetTorrentTrackers but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentTrackersContext(ctx context.Context, torrent *Torrent) ([]*Tracker, error) {
+	return t.GetTorrentTrackersByHashContext(ctx, torrent.Hash)
+}
+
+// GetTorrentTrackersByHash
+// Get torrent trackers, including the DHT, PeX and LSD pseudo trackers.
+func (t *torrentsApi) GetTorrentTrackersByHash(hash string) ([]*Tracker, error) {
+	return t.GetTorrentTrackersByHashContext(context.Background(), hash)
+}
+
+// GetTorrentTrackersByHashContext is like GetTorrentTrackersByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentTrackersByHashContext(ctx context.Context, hash string) ([]*Tracker, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetTrackers, map[string]string{
+		"hash": hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*Tracker
+	if err = json.Unmarshal(body, &trackers); err != nil {
+		return nil, err
+	}
+	return trackers, nil
+}
+
+// AddTrackers Add trackers to a torrent.
+func (t *torrentsApi) AddTrackers(torrent *Torrent, urls ...string) error {
+	return t.AddTrackersContext(context.Background(), torrent, urls...)
+}
+
+// AddTrackersContext is like AddTrackers but carries ctx for cancellation.
+func (t *torrentsApi) AddTrackersContext(ctx context.Context, torrent *Torrent, urls ...string) error {
+	return t.AddTrackersByHashContext(ctx, torrent.Hash, urls...)
+}
+
+func (t *torrentsApi) AddTrackersByHash(hash string, urls ...string) error {
+	return t.AddTrackersByHashContext(context.Background(), hash, urls...)
+}
+
+// AddTrackersByHashContext is like AddTrackersByHash but carries ctx for cancellation.
+func (t *torrentsApi) AddTrackersByHashContext(ctx context.Context, hash string, urls ...string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	for _, u := range urls {
+		if err := validateTrackerURL(u); err != nil {
+			return err
+		}
+	}
+
+	return t.actionByParams(ctx, newAction(actionAddTrackers).
+		setParam("hash", hash).
+		setParam("urls", strings.Join(urls, "\n")))
+}
+
+// EditTracker Replace the tracker origURL of a torrent with newURL.
+// The server answers ErrConflict if newURL already exists or origURL was not found.
+func (t *torrentsApi) EditTracker(torrent *Torrent, origURL, newURL string) error {
+	return t.EditTrackerContext(context.Background(), torrent, origURL, newURL)
+}
+
+// EditTrackerContext is like EditTracker but carries ctx for cancellation.
+func (t *torrentsApi) EditTrackerContext(ctx context.Context, torrent *Torrent, origURL, newURL string) error {
+	return t.EditTrackerByHashContext(ctx, torrent.Hash, origURL, newURL)
+}
+
+func (t *torrentsApi) EditTrackerByHash(hash, origURL, newURL string) error {
+	return t.EditTrackerByHashContext(context.Background(), hash, origURL, newURL)
+}
+
+// EditTrackerByHashContext is like EditTrackerByHash but carries ctx for cancellation.
+func (t *torrentsApi) EditTrackerByHashContext(ctx context.Context, hash, origURL, newURL string) error {
+	if err := validateTrackerURL(newURL); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(actionEditTracker).
+		setParam("hash", hash).
+		setParam("origUrl", origURL).
+		setParam("newUrl", newURL))
+}
+
+// RemoveTrackers Remove trackers from a torrent.
+func (t *torrentsApi) RemoveTrackers(torrent *Torrent, urls ...string) error {
+	return t.RemoveTrackersContext(context.Background(), torrent, urls...)
+}
+
+// RemoveTrackersContext is like RemoveTrackers but carries ctx for cancellation.
+func (t *torrentsApi) RemoveTrackersContext(ctx context.Context, torrent *Torrent, urls ...string) error {
+	return t.RemoveTrackersByHashContext(ctx, torrent.Hash, urls...)
+}
+
+func (t *torrentsApi) RemoveTrackersByHash(hash string, urls ...string) error {
+	return t.RemoveTrackersByHashContext(context.Background(), hash, urls...)
+}
+
+// RemoveTrackersByHashContext is like RemoveTrackersByHash but carries ctx for cancellation.
+func (t *torrentsApi) RemoveTrackersByHashContext(ctx context.Context, hash string, urls ...string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	for _, u := range urls {
+		if strings.Contains(u, "|") {
+			return errors.Errorf("invalid tracker url %q: contains '|'", u)
+		}
+	}
+
+	return t.actionByParams(ctx, newAction(actionRemoveTrackers).
+		setParam("hash", hash).
+		setParam("urls", strings.Join(urls, "|")))
+}
+
+func validateTrackerURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid tracker url %q", rawURL)
+	}
+	switch u.Scheme {
+	case "http", "https", "udp", "ws", "wss":
+	default:
+		return errors.Errorf("invalid tracker url %q: unsupported scheme", rawURL)
+	}
+	if u.Host == "" {
+		return errors.Errorf("invalid tracker url %q: missing host", rawURL)
+	}
+	// removeTrackers and editTracker split on '|'
+	if strings.Contains(rawURL, "|") {
+		return errors.Errorf("invalid tracker url %q: contains '|'", rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package qbittorrent_api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTrackers(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	trackers := map[string]bool{"https://old.example.com/announce": true}
+	srv.handle("torrents/trackers", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"url":"** [DHT] **","status":2,"tier":-1,"num_peers":12,"msg":""},
+			{"url":"https://old.example.com/announce","status":4,"tier":0,"num_peers":0,"num_seeds":-1,"msg":"timed out"}]`))
+	})
+	srv.handle("torrents/addTrackers", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		for _, u := range splitNonEmpty(r.PostForm.Get("urls"), "\n") {
+			trackers[u] = true
+		}
+	})
+	srv.handle("torrents/editTracker", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		orig, next := r.PostForm.Get("origUrl"), r.PostForm.Get("newUrl")
+		if !trackers[orig] || trackers[next] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(trackers, orig)
+		trackers[next] = true
+	})
+	srv.handle("torrents/removeTrackers", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		for _, u := range splitNonEmpty(r.PostForm.Get("urls"), "|") {
+			delete(trackers, u)
+		}
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	list, err := c.GetTorrentTrackersByHash("aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || !list[0].IsPseudo() || list[0].Status != TrackerWorking ||
+		list[1].Status != TrackerNotWorking || list[1].Status.String() != "not working" || list[1].Msg != "timed out" {
+		t.Errorf("unexpected trackers %+v", list)
+	}
+
+	torrent := &Torrent{Hash: "aaa"}
+	if err := c.AddTrackers(torrent, "udp://a.example.com:1337", "https://b.example.com/announce"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EditTracker(torrent, "https://old.example.com/announce", "https://new.example.com/announce"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EditTracker(torrent, "https://old.example.com/announce", "https://new.example.com/announce"); !errors.Is(err, ErrConflict) {
+		t.Errorf("got %v, want ErrConflict", err)
+	}
+	if err := c.RemoveTrackers(torrent, "udp://a.example.com:1337", "https://b.example.com/announce"); err != nil {
+		t.Fatal(err)
+	}
+	if len(trackers) != 1 || !trackers["https://new.example.com/announce"] {
+		t.Errorf("unexpected trackers %v", trackers)
+	}
+
+	for _, u := range []string{"", "example.com/announce", "ftp://example.com", "http:///announce", "http://a.example.com/announce|http://b.example.com"} {
+		if err := c.AddTrackersByHash("aaa", u); err == nil {
+			t.Errorf("invalid tracker url %q accepted", u)
+		}
+	}
+	if err := c.RemoveTrackersByHash("aaa", "http://a.example.com/announce|http://b.example.com"); err == nil {
+		t.Error("tracker url with '|' removed")
+	}
+}
+
+func splitNonEmpty(s, sep string) []string {
+	var res []string
+	for _, v := range strings.Split(s, sep) {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
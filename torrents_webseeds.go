@@ -0,0 +1,161 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	actionGetWebSeeds    torrentsAction = "webseeds"
+	actionAddWebSeeds    torrentsAction = "addWebSeeds"
+	actionEditWebSeed    torrentsAction = "editWebSeed"
+	actionRemoveWebSeeds torrentsAction = "removeWebSeeds"
+)
+
+type WebSeed struct {
+	URL string `json:"url"` // Web seed URL
+}
+
+// GetTorrentWebSeeds
+// Get torrent web seeds.
+func (t *torrentsApi) GetTorrentWebSeeds(torrent *Torrent) ([]*WebSeed, error) {
+	return t.GetTorrentWebSeedsContext(context.Background(), torrent)
+}
+
+// GetTorrentWebSeedsContext is like GetTorrentWebSeeds but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentWebSeedsContext(ctx context.Context, torrent *Torrent) ([]*WebSeed, error) {
+	return t.GetTorrentWebSeedsByHashContext(ctx, torrent.Hash)
+}
+
+// GetTorrentWebSeedsByHash
+// Get torrent web seeds.
+func (t *torrentsApi) GetTorrentWebSeedsByHash(hash string) ([]*WebSeed, error) {
+	return t.GetTorrentWebSeedsByHashContext(context.Background(), hash)
+}
+
+// GetTorrentWebSeedsByHashContext is like GetTorrentWebSeedsByHash but carries ctx for cancellation.
+func (t *torrentsApi) GetTorrentWebSeedsByHashContext(ctx context.Context, hash string) ([]*WebSeed, error) {
+	_, body, err := t.getForTorrent(ctx, actionGetWebSeeds, map[string]string{
+		"hash": hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var seeds []*WebSeed
+	if err = json.Unmarshal(body, &seeds); err != nil {
+		return nil, err
+	}
+	return seeds, nil
+}
+
+// AddWebSeeds Add web seeds to a torrent.
+func (t *torrentsApi) AddWebSeeds(torrent *Torrent, urls ...string) error {
+	return t.AddWebSeedsContext(context.Background(), torrent, urls...)
+}
+
+// AddWebSeedsContext is like AddWebSeeds but carries ctx for cancellation.
+func (t *torrentsApi) AddWebSeedsContext(ctx context.Context, torrent *Torrent, urls ...string) error {
+	return t.AddWebSeedsByHashContext(ctx, torrent.Hash, urls...)
+}
+
+func (t *torrentsApi) AddWebSeedsByHash(hash string, urls ...string) error {
+	return t.AddWebSeedsByHashContext(context.Background(), hash, urls...)
+}
+
+// AddWebSeedsByHashContext is like AddWebSeedsByHash but carries ctx for cancellation.
+func (t *torrentsApi) AddWebSeedsByHashContext(ctx context.Context, hash string, urls ...string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	for _, u := range urls {
+		if err := validateWebSeedURL(u); err != nil {
+			return err
+		}
+	}
+	if err := t.client.requireFeatures(ctx, featureWebSeeds); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(actionAddWebSeeds).
+		setParam("hash", hash).
+		setParam("urls", strings.Join(urls, "|")))
+}
+
+// EditWebSeed Replace the web seed origURL of a torrent with newURL.
+func (t *torrentsApi) EditWebSeed(torrent *Torrent, origURL, newURL string) error {
+	return t.EditWebSeedContext(context.Background(), torrent, origURL, newURL)
+}
+
+// EditWebSeedContext is like EditWebSeed but carries ctx for cancellation.
+func (t *torrentsApi) EditWebSeedContext(ctx context.Context, torrent *Torrent, origURL, newURL string) error {
+	return t.EditWebSeedByHashContext(ctx, torrent.Hash, origURL, newURL)
+}
+
+func (t *torrentsApi) EditWebSeedByHash(hash, origURL, newURL string) error {
+	return t.EditWebSeedByHashContext(context.Background(), hash, origURL, newURL)
+}
+
+// EditWebSeedByHashContext is like EditWebSeedByHash but carries ctx for cancellation.
+func (t *torrentsApi) EditWebSeedByHashContext(ctx context.Context, hash, origURL, newURL string) error {
+	if err := validateWebSeedURL(newURL); err != nil {
+		return err
+	}
+	if err := t.client.requireFeatures(ctx, featureWebSeeds); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(actionEditWebSeed).
+		setParam("hash", hash).
+		setParam("origUrl", origURL).
+		setParam("newUrl", newURL))
+}
+
+// RemoveWebSeeds Remove web seeds from a torrent.
+func (t *torrentsApi) RemoveWebSeeds(torrent *Torrent, urls ...string) error {
+	return t.RemoveWebSeedsContext(context.Background(), torrent, urls...)
+}
+
+// RemoveWebSeedsContext is like RemoveWebSeeds but carries ctx for cancellation.
+func (t *torrentsApi) RemoveWebSeedsContext(ctx context.Context, torrent *Torrent, urls ...string) error {
+	return t.RemoveWebSeedsByHashContext(ctx, torrent.Hash, urls...)
+}
+
+func (t *torrentsApi) RemoveWebSeedsByHash(hash string, urls ...string) error {
+	return t.RemoveWebSeedsByHashContext(context.Background(), hash, urls...)
+}
+
+// RemoveWebSeedsByHashContext is like RemoveWebSeedsByHash but carries ctx for cancellation.
+func (t *torrentsApi) RemoveWebSeedsByHashContext(ctx context.Context, hash string, urls ...string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	if err := t.client.requireFeatures(ctx, featureWebSeeds); err != nil {
+		return err
+	}
+
+	return t.actionByParams(ctx, newAction(actionRemoveWebSeeds).
+		setParam("hash", hash).
+		setParam("urls", strings.Join(urls, "|")))
+}
+
+func validateWebSeedURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid web seed url %q", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("invalid web seed url %q: unsupported scheme", rawURL)
+	}
+	if u.Host == "" {
+		return errors.Errorf("invalid web seed url %q: missing host", rawURL)
+	}
+	if strings.Contains(rawURL, "|") {
+		return errors.Errorf("invalid web seed url %q: contains '|'", rawURL)
+	}
+	return nil
+}
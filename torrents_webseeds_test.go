@@ -0,0 +1,65 @@
+package qbittorrent_api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWebSeeds(t *testing.T) {
+	srv := newFakeServerWithVersion("2.11.2")
+	defer srv.Close()
+	var added, removed, orig, next string
+	srv.handle("torrents/webseeds", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"url":"https://mirror1.example.com/data/"},{"url":"http://mirror2.example.com/data/"}]`))
+	})
+	srv.handle("torrents/addWebSeeds", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		added = r.PostForm.Get("urls")
+	})
+	srv.handle("torrents/editWebSeed", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		orig, next = r.PostForm.Get("origUrl"), r.PostForm.Get("newUrl")
+	})
+	srv.handle("torrents/removeWebSeeds", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		removed = r.PostForm.Get("urls")
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	torrent := &Torrent{Hash: "aaa"}
+	seeds, err := c.GetTorrentWebSeeds(torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeds) != 2 || seeds[1].URL != "http://mirror2.example.com/data/" {
+		t.Errorf("unexpected web seeds %+v", seeds)
+	}
+
+	if err := c.AddWebSeeds(torrent, "https://a.example.com/", "https://b.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EditWebSeed(torrent, "https://a.example.com/", "https://c.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveWebSeeds(torrent, "https://b.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if added != "https://a.example.com/|https://b.example.com/" || orig != "https://a.example.com/" ||
+		next != "https://c.example.com/" || removed != "https://b.example.com/" {
+		t.Errorf("added %q, edited %q to %q, removed %q", added, orig, next, removed)
+	}
+
+	for _, u := range []string{"", "mirror.example.com", "udp://mirror.example.com", "https://"} {
+		if err := c.AddWebSeedsByHash("aaa", u); err == nil {
+			t.Errorf("invalid web seed url %q accepted", u)
+		}
+	}
+
+	old := newFakeServerWithVersion("2.9.3")
+	defer old.Close()
+	c = NewClient(old.URL, WithAuth(name, pwd))
+	if err := c.AddWebSeeds(torrent, "https://a.example.com/"); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("got %v, want ErrUnsupportedByServer", err)
+	}
+}
@@ -0,0 +1,195 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type transferApi struct {
+	client *Client
+}
+type transferAction = string
+
+const (
+	actionTransferInfo          transferAction = "info"
+	actionSpeedLimitsMode       transferAction = "speedLimitsMode"
+	actionToggleSpeedLimitsMode transferAction = "toggleSpeedLimitsMode"
+	actionGlobalDownloadLimit   transferAction = "downloadLimit"
+	actionSetGlobalDlLimit      transferAction = "setDownloadLimit"
+	actionGlobalUploadLimit     transferAction = "uploadLimit"
+	actionSetGlobalUpLimit      transferAction = "setUploadLimit"
+	actionBanPeers              transferAction = "banPeers"
+)
+
+type ConnectionStatus = string
+
+const (
+	ConnectionStatusConnected    ConnectionStatus = "connected"
+	ConnectionStatusFirewalled   ConnectionStatus = "firewalled"
+	ConnectionStatusDisconnected ConnectionStatus = "disconnected"
+)
+
+type TransferInfo struct {
+	DlInfoSpeed      int64            `json:"dl_info_speed"`     // Global download rate (bytes/s)
+	DlInfoData       int64            `json:"dl_info_data"`      // Data downloaded this session (bytes)
+	UpInfoSpeed      int64            `json:"up_info_speed"`     // Global upload rate (bytes/s)
+	UpInfoData       int64            `json:"up_info_data"`      // Data uploaded this session (bytes)
+	DlRateLimit      int64            `json:"dl_rate_limit"`     // Download rate limit (bytes/s)
+	UpRateLimit      int64            `json:"up_rate_limit"`     // Upload rate limit (bytes/s)
+	DHTNodes         int              `json:"dht_nodes"`         // DHT nodes connected to
+	ConnectionStatus ConnectionStatus `json:"connection_status"` // Connection status
+	Queueing         bool             `json:"queueing"`          // True if torrent queueing is enabled
+	UseAltSpeed      bool             `json:"use_alt_speed_limits"`
+	RefreshInterval  int              `json:"refresh_interval"` // ms, WebUI refresh interval
+}
+
+// TransferInfo Retrieve the global transfer info shown in the WebUI status bar.
+func (t *transferApi) TransferInfo() (*TransferInfo, error) {
+	return t.TransferInfoContext(context.Background())
+}
+
+// TransferInfoContext is like TransferInfo but carries ctx for cancellation.
+func (t *transferApi) TransferInfoContext(ctx context.Context) (*TransferInfo, error) {
+	body, err := t.getForTransfer(ctx, actionTransferInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info TransferInfo
+	if err = json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AltSpeedLimitsEnabled reports whether the alternative speed limits are active.
+func (t *transferApi) AltSpeedLimitsEnabled() (bool, error) {
+	return t.AltSpeedLimitsEnabledContext(context.Background())
+}
+
+// AltSpeedLimitsEnabledContext is like AltSpeedLimitsEnabled but carries ctx for cancellation.
+func (t *transferApi) AltSpeedLimitsEnabledContext(ctx context.Context) (bool, error) {
+	body, err := t.getForTransfer(ctx, actionSpeedLimitsMode, nil)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(body)) == "1", nil
+}
+
+// ToggleSpeedLimitsMode Switch between the normal and the alternative speed limits.
+func (t *transferApi) ToggleSpeedLimitsMode() error {
+	return t.ToggleSpeedLimitsModeContext(context.Background())
+}
+
+// ToggleSpeedLimitsModeContext is like ToggleSpeedLimitsMode but carries ctx for cancellation.
+func (t *transferApi) ToggleSpeedLimitsModeContext(ctx context.Context) error {
+	_, err := t.postForTransfer(ctx, actionToggleSpeedLimitsMode, nil)
+	return err
+}
+
+// SetAltSpeedLimits enables or disables the alternative speed limits,
+// toggling the mode only if it differs.
+func (t *transferApi) SetAltSpeedLimits(enabled bool) error {
+	return t.SetAltSpeedLimitsContext(context.Background(), enabled)
+}
+
+// SetAltSpeedLimitsContext is like SetAltSpeedLimits but carries ctx for cancellation.
+func (t *transferApi) SetAltSpeedLimitsContext(ctx context.Context, enabled bool) error {
+	current, err := t.AltSpeedLimitsEnabledContext(ctx)
+	if err != nil || current == enabled {
+		return err
+	}
+	return t.ToggleSpeedLimitsModeContext(ctx)
+}
+
+// GlobalDownloadLimit Retrieve the global download limit in bytes/s, 0 if unlimited.
+func (t *transferApi) GlobalDownloadLimit() (int64, error) {
+	return t.GlobalDownloadLimitContext(context.Background())
+}
+
+// GlobalDownloadLimitContext is like GlobalDownloadLimit but carries ctx for cancellation.
+func (t *transferApi) GlobalDownloadLimitContext(ctx context.Context) (int64, error) {
+	return t.getLimit(ctx, actionGlobalDownloadLimit)
+}
+
+// SetGlobalDownloadLimit Set the global download limit in bytes/s, 0 for unlimited.
+func (t *transferApi) SetGlobalDownloadLimit(limit int64) error {
+	return t.SetGlobalDownloadLimitContext(context.Background(), limit)
+}
+
+// SetGlobalDownloadLimitContext is like SetGlobalDownloadLimit but carries ctx for cancellation.
+func (t *transferApi) SetGlobalDownloadLimitContext(ctx context.Context, limit int64) error {
+	return t.setLimit(ctx, actionSetGlobalDlLimit, limit)
+}
+
+// GlobalUploadLimit Retrieve the global upload limit in bytes/s, 0 if unlimited.
+func (t *transferApi) GlobalUploadLimit() (int64, error) {
+	return t.GlobalUploadLimitContext(context.Background())
+}
+
+// GlobalUploadLimitContext is like GlobalUploadLimit but carries ctx for cancellation.
+func (t *transferApi) GlobalUploadLimitContext(ctx context.Context) (int64, error) {
+	return t.getLimit(ctx, actionGlobalUploadLimit)
+}
+
+// SetGlobalUploadLimit Set the global upload limit in bytes/s, 0 for unlimited.
+func (t *transferApi) SetGlobalUploadLimit(limit int64) error {
+	return t.SetGlobalUploadLimitContext(context.Background(), limit)
+}
+
+// SetGlobalUploadLimitContext is like SetGlobalUploadLimit but carries ctx for cancellation.
+func (t *transferApi) SetGlobalUploadLimitContext(ctx context.Context, limit int64) error {
+	return t.setLimit(ctx, actionSetGlobalUpLimit, limit)
+}
+
+// BanPeers Ban peers permanently.
+func (t *transferApi) BanPeers(peers ...PeerAddr) error {
+	return t.BanPeersContext(context.Background(), peers...)
+}
+
+// BanPeersContext is like BanPeers but carries ctx for cancellation.
+func (t *transferApi) BanPeersContext(ctx context.Context, peers ...PeerAddr) error {
+	if len(peers) == 0 {
+		return nil
+	}
+	addrs, err := joinPeerAddrs(peers)
+	if err != nil {
+		return err
+	}
+	_, err = t.postForTransfer(ctx, actionBanPeers, map[string]string{"peers": addrs})
+	return err
+}
+
+func (t *transferApi) getLimit(ctx context.Context, action transferAction) (int64, error) {
+	body, err := t.getForTransfer(ctx, action, nil)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(body)), 10, 64)
+}
+
+func (t *transferApi) setLimit(ctx context.Context, action transferAction, limit int64) error {
+	if limit < 0 {
+		limit = 0
+	}
+	_, err := t.postForTransfer(ctx, action, map[string]string{"limit": strconv.FormatInt(limit, 10)})
+	return err
+}
+
+func (t *transferApi) getForTransfer(ctx context.Context, action transferAction, data map[string]string) ([]byte, error) {
+	resp, err := t.client.request.get(ctx, apiNameTransfer, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameTransfer, action, resp)
+}
+
+func (t *transferApi) postForTransfer(ctx context.Context, action transferAction, data map[string]string) ([]byte, error) {
+	resp, err := t.client.request.post(ctx, apiNameTransfer, action, data)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(apiNameTransfer, action, resp)
+}
@@ -0,0 +1,71 @@
+package qbittorrent_api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	alt, dlLimit, toggles := false, "0", 0
+	srv.handle("transfer/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"connection_status":"firewalled","dht_nodes":386,"dl_info_data":681521119,"dl_info_speed":0,"dl_rate_limit":0,"up_info_data":10747904,"up_info_speed":0,"up_rate_limit":1048576}`))
+	})
+	srv.handle("transfer/speedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if alt {
+			w.Write([]byte("1"))
+		} else {
+			w.Write([]byte("0"))
+		}
+	})
+	srv.handle("transfer/toggleSpeedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		alt = !alt
+		toggles++
+	})
+	srv.handle("transfer/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write([]byte(dlLimit))
+	})
+	srv.handle("transfer/setDownloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		r.ParseForm()
+		dlLimit = r.PostForm.Get("limit")
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	info, err := c.TransferInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ConnectionStatus != ConnectionStatusFirewalled || info.DHTNodes != 386 || info.DlInfoData != 681521119 || info.UpRateLimit != 1048576 {
+		t.Errorf("unexpected transfer info %+v", info)
+	}
+
+	if err := c.SetGlobalDownloadLimit(2048); err != nil {
+		t.Fatal(err)
+	}
+	if limit, err := c.GlobalDownloadLimit(); err != nil || limit != 2048 {
+		t.Errorf("got limit %d, %v", limit, err)
+	}
+
+	for _, enabled := range []bool{true, true, false} {
+		if err := c.SetAltSpeedLimits(enabled); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.AltSpeedLimitsEnabled(); err != nil || got != enabled {
+			t.Errorf("got alt speed %v, %v, want %v", got, err, enabled)
+		}
+	}
+	if toggles != 2 {
+		t.Errorf("toggled %d times, want 2", toggles)
+	}
+}
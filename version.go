@@ -0,0 +1,138 @@
+package qbittorrent_api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// APIVersion is a Web API version like 2.8.3, see WebAPIVersion.
+type APIVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseAPIVersion parses versions like "2.8.3", a leading "v" is allowed.
+func ParseAPIVersion(s string) (APIVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return APIVersion{}, errors.Errorf("invalid Web API version %q", s)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return APIVersion{}, errors.Errorf("invalid Web API version %q", s)
+		}
+		numbers[i] = n
+	}
+	return APIVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (v APIVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or
+// higher than o.
+func (v APIVersion) Compare(o APIVersion) int {
+	for _, d := range [3]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether v is o or newer.
+func (v APIVersion) AtLeast(o APIVersion) bool {
+	return v.Compare(o) >= 0
+}
+
+// feature is part of the Web API which only exists since a certain version.
+type feature struct {
+	name  string
+	since APIVersion
+}
+
+var (
+	featureContentLayout = feature{"contentLayout", APIVersion{2, 7, 0}}
+	featureRenamePaths   = feature{"renameFile/renameFolder paths", APIVersion{2, 7, 0}}
+	featureTagFilter     = feature{"tag filter", APIVersion{2, 8, 3}}
+	featureDownloadPath  = feature{"download path", APIVersion{2, 8, 4}}
+	// qBittorrent 5 renamed pause/resume to stop/start, including the
+	// "paused" add parameter and the paused/resumed status filters
+	featureStopStart = feature{"torrents/stop", APIVersion{2, 11, 0}}
+	// adding, editing and removing web seeds came with qBittorrent 5
+	featureWebSeeds = feature{"web seed editing", APIVersion{2, 10, 3}}
+)
+
+// apiVersionCache holds the Web API version of the server once it is known.
+type apiVersionCache struct {
+	mu      sync.Mutex
+	version *APIVersion
+}
+
+// ServerAPIVersion returns the Web API version of the connected server. It is
+// fetched on first use and cached for the lifetime of the Client.
+func (a *applicationApi) ServerAPIVersion() (APIVersion, error) {
+	return a.ServerAPIVersionContext(context.Background())
+}
+
+// ServerAPIVersionContext is like ServerAPIVersion but carries ctx for cancellation.
+func (a *applicationApi) ServerAPIVersionContext(ctx context.Context) (APIVersion, error) {
+	a.versionCache.mu.Lock()
+	cached := a.versionCache.version
+	a.versionCache.mu.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	s, err := a.WebAPIVersionContext(ctx)
+	if err != nil {
+		return APIVersion{}, err
+	}
+	version, err := ParseAPIVersion(s)
+	if err != nil {
+		return APIVersion{}, err
+	}
+
+	a.versionCache.mu.Lock()
+	a.versionCache.version = &version
+	a.versionCache.mu.Unlock()
+	return version, nil
+}
+
+// supports reports whether the server has the feature.
+func (a *applicationApi) supports(ctx context.Context, f feature) (bool, error) {
+	version, err := a.ServerAPIVersionContext(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get Web API version")
+	}
+	return version.AtLeast(f.since), nil
+}
+
+// requireFeatures returns ErrUnsupportedByServer if the server lacks one of
+// the features. The version is only fetched if there is a feature to check.
+func (a *applicationApi) requireFeatures(ctx context.Context, features ...feature) error {
+	for _, f := range features {
+		ok, err := a.supports(ctx, f)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			version, _ := a.ServerAPIVersionContext(ctx)
+			return errors.Wrapf(ErrUnsupportedByServer, "%s requires Web API %s, server has %s", f.name, f.since, version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package qbittorrent_api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    APIVersion
+		wantErr bool
+	}{
+		{name: "full", src: "2.8.3", want: APIVersion{2, 8, 3}},
+		{name: "two parts", src: "2.11", want: APIVersion{2, 11, 0}},
+		{name: "prefix and newline", src: "v2.9.2\n", want: APIVersion{2, 9, 2}},
+		{name: "empty", src: "", wantErr: true},
+		{name: "garbage", src: "2.x.1", wantErr: true},
+		{name: "too long", src: "2.8.3.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAPIVersion(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAPIVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAPIVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIVersionCompare(t *testing.T) {
+	if !(APIVersion{2, 11, 0}).AtLeast(APIVersion{2, 8, 3}) {
+		t.Error("2.11.0 should be at least 2.8.3")
+	}
+	if (APIVersion{2, 8, 2}).AtLeast(APIVersion{2, 8, 3}) {
+		t.Error("2.8.2 should not be at least 2.8.3")
+	}
+	if c := (APIVersion{2, 8, 3}).Compare(APIVersion{2, 8, 3}); c != 0 {
+		t.Errorf("Compare() = %d, want 0", c)
+	}
+}
+
+func TestFeatureGating(t *testing.T) {
+	srv := newFakeServerWithVersion("2.6.0")
+	defer srv.Close()
+	versionRequests := 0
+	srv.handle("app/webapiVersion", func(w http.ResponseWriter, r *http.Request) {
+		versionRequests++
+		w.Write([]byte("2.6.0"))
+	})
+	srv.handle("torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+
+	c := NewClient(srv.URL, WithAuth(name, pwd))
+	if _, err := c.Torrents(&Filter{Tag: "tag1"}); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("Torrents() error = %v, want %v", err, ErrUnsupportedByServer)
+	}
+	if err := c.DownloadFromLink(MagnetDLConfig{
+		Urls:               []string{"magnet:?xt=urn:btih:076e0288a6147459d261ee1a55c18f1f91a241c2"},
+		DownloadBaseConfig: DownloadBaseConfig{ContentLayout: ContentLayoutSubFolder},
+	}); !errors.Is(err, ErrUnsupportedByServer) {
+		t.Errorf("DownloadFromLink() error = %v, want %v", err, ErrUnsupportedByServer)
+	}
+	if _, err := c.Torrents(&Filter{}); err != nil {
+		t.Error(err)
+	}
+	if versionRequests != 1 {
+		t.Errorf("webapiVersion requested %d times, want 1", versionRequests)
+	}
+}